@@ -0,0 +1,97 @@
+package main
+
+// ============================
+// AVL mode.
+// An AVL tree keeps the heights of the left and right subtrees of every node within 1 of each other,
+// so the height of the tree stays O(log n) and so do Insert, Delete and Contains.
+// ============================
+
+// Get a new empty BST in AVL mode, which rebalances itself after every Insert and Delete.
+func NewAVL() *BST {
+	return &BST{avl: true}
+}
+
+// Get a new BST in AVL mode from a slice. The tree built by NewFromSlice is already balanced.
+func NewAVLFromSlice(values []int) *BST {
+	b := NewFromSlice(values)
+	b.avl = true
+	return b
+}
+
+// Check if the tree rebalances itself after every Insert and Delete.
+func (b *BST) IsAVL() bool {
+	return b.avl
+}
+
+// Get the difference between the heights of the left and right subtrees of a node.
+func balanceFactor(n *Node) int {
+	return int(nodeHeight(n.Left)) - int(nodeHeight(n.Right))
+}
+
+// A node of a binary tree with parent pointers and cached heights: Node of BST and mapNode of TreeMap.
+// TreeMap needs nodes with generic keys and values, and Node can't become generic without changing
+// the API of BST, so both implement this interface and share the rotations and the AVL rebalancing
+// instead of keeping two copies of them. N is the pointer type of the node itself.
+type avlNode[N any] interface {
+	comparable
+	left() N
+	right() N
+	parent() N
+	setLeft(child N)
+	setRight(child N)
+	setParent(parent N)
+	subtreeHeight() uint // Cached height of the subtree, the node is never nil.
+	update()             // Recompute the cached values of the node from its children.
+}
+
+func (n *Node) left() *Node            { return n.Left }
+func (n *Node) right() *Node           { return n.Right }
+func (n *Node) parent() *Node          { return n.Parent }
+func (n *Node) setLeft(child *Node)    { n.Left = child }
+func (n *Node) setRight(child *Node)   { n.Right = child }
+func (n *Node) setParent(parent *Node) { n.Parent = parent }
+func (n *Node) subtreeHeight() uint    { return nodeHeight(n) }
+
+// Get the difference between the heights of the left and right subtrees of a node of any AVL tree.
+func avlBalanceFactor[N avlNode[N]](n N) int {
+	height := func(n N) uint {
+		var none N
+		if n == none {
+			return 0
+		}
+		return n.subtreeHeight()
+	}
+	return int(height(n.left())) - int(height(n.right()))
+}
+
+// Restore the AVL property at node n, whose subtrees are balanced and differ in height by at most 2.
+// Return the node that took n's place, which is n itself if no rotation was needed.
+//
+// If the left subtree is too high, a right rotation around n fixes it, unless the extra height is
+// in the right subtree of the left child (left-right case). That one would end up under n after
+// the rotation, so the left child is first rotated left. The right side is symmetric.
+func rebalance[N avlNode[N]](n N) N {
+	n.update()
+	switch factor := avlBalanceFactor(n); {
+	case factor > 1:
+		if avlBalanceFactor(n.left()) < 0 {
+			leftRotation(n.left())
+		}
+		n = rightRotation(n)
+	case factor < -1:
+		if avlBalanceFactor(n.right()) > 0 {
+			rightRotation(n.right())
+		}
+		n = leftRotation(n)
+	}
+	return n
+}
+
+// Rebalance node n, see rebalance, and keep the root of the tree up to date.
+func (b *BST) rebalanceNode(n *Node) *Node {
+	n = rebalance(n)
+	if n.Parent == nil {
+		b.Root = n
+	}
+	return n
+}
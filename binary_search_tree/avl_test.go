@@ -0,0 +1,150 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Check the structure of the tree: parent pointers, cached heights and sizes, size and order of values.
+// If the tree is in AVL mode, also check that IsBalanced holds for the subtree of every node.
+// Only nodes in multiset mode can have more than one copy of their value.
+func checkTree(t *testing.T, b *BST) {
+	t.Helper()
+	if b.Root != nil && b.Root.Parent != nil {
+		t.Fatalf("Root %d has parent %d", b.Root.Value, b.Root.Parent.Value)
+	}
+	count := uint(0)
+	var check func(n *Node)
+	check = func(n *Node) {
+		if n == nil {
+			return
+		}
+		if copies(n) > 1 && !b.multiset {
+			t.Fatalf("Node %d has %d copies", n.Value, copies(n))
+		}
+		count += copies(n)
+		for _, child := range []*Node{n.Left, n.Right} {
+			if child != nil && child.Parent != n {
+				t.Fatalf("Node %d is a child of %d, but its parent is %v", child.Value, n.Value, child.Parent)
+			}
+		}
+		if n.height != height(n) {
+			t.Fatalf("Node %d has cached height %d, want %d", n.Value, n.height, height(n))
+		}
+		if n.size != uint(len(inOrderRecursive(n, nil))) {
+			t.Fatalf("Node %d has cached size %d, want %d", n.Value, n.size, len(inOrderRecursive(n, nil)))
+		}
+		if b.avl && !(&BST{Root: n}).IsBalanced() {
+			t.Fatalf("Subtree of node %d is not balanced", n.Value)
+		}
+		check(n.Left)
+		check(n.Right)
+	}
+	check(b.Root)
+	if count != b.Size {
+		t.Fatalf("Tree has %d values, but Size is %d", count, b.Size)
+	}
+	values := b.InOrderIterative()
+	for i := 1; i < len(values); i++ {
+		if values[i-1] > values[i] || values[i-1] == values[i] && !b.multiset {
+			t.Fatalf("Tree is not a valid BST: %v", values)
+		}
+	}
+}
+
+func TestAVL_InsertSorted(t *testing.T) {
+	// Sorted insertion degrades a plain BST to a list, an AVL tree stays logarithmic.
+	avl := NewAVL()
+	plain := NewEmpty()
+	for value := 1; value <= 1000; value++ {
+		avl.Insert(value)
+		plain.Insert(value)
+		checkTree(t, avl)
+	}
+	if plain.Height() != 1000 {
+		t.Errorf("Expected plain BST height 1000, got %d", plain.Height())
+	}
+	// The height of an AVL tree is below 1.44 log2(n + 2).
+	if limit := uint(1.44 * math.Log2(1002)); avl.Height() > limit {
+		t.Errorf("Expected AVL height at most %d, got %d", limit, avl.Height())
+	}
+	if !avl.IsAVL() || plain.IsAVL() {
+		t.Errorf("Expected only the AVL tree to be in AVL mode")
+	}
+}
+
+func TestAVL_Rotations(t *testing.T) {
+	// Each order of 3 values needs a different rotation, all result in 2 at the root.
+	for _, values := range [][]int{{1, 2, 3}, {3, 2, 1}, {3, 1, 2}, {1, 3, 2}} {
+		avl := NewAVL()
+		avl.Insert(values...)
+		checkTree(t, avl)
+		if !reflect.DeepEqual(avl.LevelOrder(), []int{2, 1, 3}) {
+			t.Errorf("Inserting %v: expected level order [2 1 3], got %v", values, avl.LevelOrder())
+		}
+	}
+}
+
+func TestAVL_Delete(t *testing.T) {
+	avl := NewAVLFromSlice([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	checkTree(t, avl)
+	// Removing the left side makes the tree lean right, unless it rebalances.
+	for _, value := range []int{1, 2, 3, 4} {
+		if err := avl.Delete(value); err != nil {
+			t.Fatalf("Delete(%d) returned error: %v", value, err)
+		}
+		checkTree(t, avl)
+	}
+	if err := avl.Delete(100); err == nil {
+		t.Errorf("Expected error when deleting a non-existent value")
+	}
+	if !reflect.DeepEqual(avl.InOrderIterative(), []int{5, 6, 7, 8, 9, 10}) {
+		t.Errorf("Unexpected values after deletion: %v", avl.InOrderIterative())
+	}
+}
+
+// Random inserts and deletes, compared with a map. Plain trees are checked too, to make sure the
+// cached heights are also kept up to date without AVL mode, including after RebalanceDSW.
+func TestAVL_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(41))
+	for _, avlMode := range []bool{true, false} {
+		b := NewEmpty()
+		if avlMode {
+			b = NewAVL()
+		}
+		present := make(map[int]bool)
+		for i := 0; i < 3000; i++ {
+			value := r.Intn(200)
+			if r.Intn(3) == 0 {
+				err := b.Delete(value)
+				if (err == nil) != present[value] {
+					t.Fatalf("Delete(%d) returned %v, value present: %v", value, err, present[value])
+				}
+				delete(present, value)
+			} else {
+				err := b.Insert(value)
+				if (err == nil) == present[value] {
+					t.Fatalf("Insert(%d) returned %v, value present: %v", value, err, present[value])
+				}
+				present[value] = true
+			}
+			checkTree(t, b)
+			if !avlMode && i%500 == 0 {
+				b.RebalanceDSW()
+				checkTree(t, b)
+			}
+		}
+
+		expected := []int{}
+		for value := range present {
+			expected = append(expected, value)
+		}
+		sort.Ints(expected)
+		if values := b.InOrderIterative(); !reflect.DeepEqual(values, expected) && len(expected) > 0 {
+			t.Fatalf("Expected values %v, got %v", expected, values)
+		}
+	}
+}
@@ -6,47 +6,138 @@ import (
 	"sort"
 )
 
+// Node of a BST. Nodes can also be built by hand and linked through Left, Right and Parent into
+// BST.Root. Their unexported fields are then 0, which means one copy of the value, and the cached
+// height and size are computed the first time they're needed. After that, change the tree only
+// through the BST methods, which keep the cached values up to date.
 type Node struct {
 	Value  int
 	Left   *Node
 	Right  *Node
 	Parent *Node
+	height uint // Cached height of the subtree rooted at this node, a leaf has height 1, 0 if not computed yet.
+	size   uint // Cached number of values in the subtree rooted at this node, counting every copy.
+	count  uint // Number of copies of the value, more than 1 only in multiset mode, see copies.
+	red    bool // Color of the node, only used by RBTree.
 }
 
 type BST struct {
-	Root *Node
-	Size uint
+	Root     *Node
+	Size     uint // Number of values in the tree, in multiset mode every copy is counted.
+	avl      bool // Rebalance after every Insert and Delete, see NewAVL.
+	multiset bool // Keep duplicates, see NewMultiset.
+	version  uint // Incremented on every modification, so that cursors can detect them.
 }
 
 func NewEmpty() *BST {
 	return &BST{}
 }
 
+// Get a new balanced BST from a slice, which is not modified. Duplicates are ignored, as Insert doesn't
+// allow them, see NewMultisetFromSlice to keep them.
 func NewFromSlice(values []int) *BST {
-	sort.Ints(values)
-	return &BST{Root: newFromSlice(values, nil), Size: uint(len(values))}
+	sorted, _ := countValues(values)
+	return &BST{Root: newFromSlice(sorted, nil), Size: uint(len(sorted))}
 }
 
-// Helper function to build a tree from a sorted slice.
+// Helper function to build a tree from a sorted slice without duplicates.
 // Use the middle element as the root and assign the left and right subtrees recursively.
 // Current node is passed as a parameter to correctly assign parents.
 func newFromSlice(values []int, current *Node) *Node {
+	return newFromCounts(values, nil, current)
+}
+
+// Same as newFromSlice, but the node of values[i] gets counts[i] copies of it. If counts is nil,
+// every node has one copy.
+func newFromCounts(values []int, counts []uint, current *Node) *Node {
 	if len(values) == 0 {
 		return nil
 	}
 	mid := len(values) / 2
-	root := &Node{Value: values[mid], Parent: current}
-	root.Left = newFromSlice(values[:mid], root)
-	root.Right = newFromSlice(values[mid+1:], root)
+	root := &Node{Value: values[mid], Parent: current, count: 1}
+	var leftCounts, rightCounts []uint
+	if counts != nil {
+		root.count = counts[mid]
+		leftCounts, rightCounts = counts[:mid], counts[mid+1:]
+	}
+	root.Left = newFromCounts(values[:mid], leftCounts, root)
+	root.Right = newFromCounts(values[mid+1:], rightCounts, root)
+	root.update()
 	return root
 }
 
+// Get the distinct values of a slice in increasing order, and the number of copies of each one.
+// The slice is not modified.
+func countValues(values []int) ([]int, []uint) {
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+	distinct, counts := []int{}, []uint{}
+	for i, value := range sorted {
+		if i > 0 && value == sorted[i-1] {
+			counts[len(counts)-1]++
+		} else {
+			distinct = append(distinct, value)
+			counts = append(counts, 1)
+		}
+	}
+	return distinct, counts
+}
+
+// Get the cached height of a subtree, 0 for an empty one. The cached values of a subtree built
+// by hand are computed first.
+func nodeHeight(n *Node) uint {
+	if n == nil {
+		return 0
+	}
+	if n.height == 0 {
+		updateSubtree(n)
+	}
+	return n.height
+}
+
+// Get the cached size of a subtree, 0 for an empty one, see nodeHeight.
+func nodeSize(n *Node) uint {
+	if n == nil {
+		return 0
+	}
+	if n.height == 0 {
+		updateSubtree(n)
+	}
+	return n.size
+}
+
+// Get the number of copies of the value of a node. A node built by hand has a count of 0,
+// which means one copy.
+func copies(n *Node) uint {
+	return max(n.count, 1)
+}
+
+// Recompute the cached height and size of the node from its children.
+func (n *Node) update() {
+	n.height = max(nodeHeight(n.Left), nodeHeight(n.Right)) + 1
+	n.size = nodeSize(n.Left) + nodeSize(n.Right) + copies(n)
+}
+
+// Update the cached heights and sizes from node n up to the root, after n's subtree has changed.
+// In AVL mode, also rebalance every node on the way.
+func (b *BST) retrace(n *Node) {
+	for n != nil {
+		if b.avl {
+			n = b.rebalanceNode(n)
+		} else {
+			n.update()
+		}
+		n = n.Parent
+	}
+}
+
 // Insert a value into the BST by going left or right depending on the value.
-// If the value already exists, return an error.
+// If the value already exists, return an error, or add a copy of it in multiset mode.
 func (b *BST) insertSingle(value int) error {
 	if b.Root == nil {
-		b.Root = &Node{Value: value}
+		b.Root = &Node{Value: value, height: 1, size: 1, count: 1}
 		b.Size = 1
+		b.version++
 		return nil
 	}
 
@@ -56,18 +147,28 @@ func (b *BST) insertSingle(value int) error {
 	for {
 		if value < current.Value {
 			if current.Left == nil {
-				current.Left = &Node{Value: value, Parent: current}
+				current.Left = &Node{Value: value, Parent: current, height: 1, size: 1, count: 1}
 				b.Size++
+				b.version++
+				b.retrace(current)
 				return nil
 			}
 			current = current.Left
 		} else if value > current.Value {
 			if current.Right == nil {
-				current.Right = &Node{Value: value, Parent: current}
+				current.Right = &Node{Value: value, Parent: current, height: 1, size: 1, count: 1}
 				b.Size++
+				b.version++
+				b.retrace(current)
 				return nil
 			}
 			current = current.Right
+		} else if b.multiset {
+			current.count = copies(current) + 1
+			b.Size++
+			b.version++
+			b.retrace(current)
+			return nil
 		} else {
 			return fmt.Errorf("Insert error: value %d already exists", value)
 		}
@@ -168,13 +269,21 @@ func (b *BST) IsBalanced() bool {
 	return heightDiff <= 1
 }
 
+// Append the value of the node to the result of a traversal, once for every copy of it.
+func appendCopies(result []int, n *Node) []int {
+	for range copies(n) {
+		result = append(result, n.Value)
+	}
+	return result
+}
+
 // Recursive in-order traversal.
 func inOrderRecursive(n *Node, result []int) []int {
 	if n == nil {
 		return result
 	}
 	result = inOrderRecursive(n.Left, result)
-	result = append(result, n.Value)
+	result = appendCopies(result, n)
 	result = inOrderRecursive(n.Right, result)
 	return result
 }
@@ -184,7 +293,7 @@ func preOrderRecursive(n *Node, result []int) []int {
 	if n == nil {
 		return result
 	}
-	result = append(result, n.Value)
+	result = appendCopies(result, n)
 	result = preOrderRecursive(n.Left, result)
 	result = preOrderRecursive(n.Right, result)
 	return result
@@ -197,7 +306,7 @@ func postOrderRecursive(n *Node, result []int) []int {
 	}
 	result = postOrderRecursive(n.Left, result)
 	result = postOrderRecursive(n.Right, result)
-	result = append(result, n.Value)
+	result = appendCopies(result, n)
 	return result
 }
 
@@ -236,7 +345,7 @@ func (b *BST) InOrderIterative() []int {
 		} else {
 			// Upon reaching a leaf, visit it and go right.
 			current = stack.Pop()
-			result = append(result, current.Value)
+			result = appendCopies(result, current)
 			current = current.Right
 		}
 	}
@@ -257,7 +366,7 @@ func (b *BST) PreOrderIterative() []int {
 	for stack.Length() > 0 {
 		// Process the current node.
 		node := stack.Pop()
-		result = append(result, node.Value)
+		result = appendCopies(result, node)
 
 		// Push right child first so that the left child is processed first.
 		if node.Right != nil {
@@ -291,7 +400,7 @@ func (b *BST) PostOrderIterative() []int {
 			if peek.Right != nil && lastVisited != peek.Right {
 				current = peek.Right
 			} else {
-				result = append(result, peek.Value)
+				result = appendCopies(result, peek)
 				lastVisited = stack.Pop()
 			}
 		}
@@ -311,7 +420,7 @@ func (b *BST) LevelOrder() []int {
 
 	for queue.Length() > 0 {
 		current := queue.Dequeue()
-		result = append(result, current.Value)
+		result = appendCopies(result, current)
 		if current.Left != nil {
 			queue.Enqueue(current.Left)
 		}
@@ -426,6 +535,8 @@ func (b *BST) deleteNode(node *Node) error {
 			}
 		}
 		b.Size--
+		b.version++
+		b.retrace(node.Parent)
 		return nil
 	}
 
@@ -452,16 +563,20 @@ func (b *BST) deleteNode(node *Node) error {
 			child.Parent = node.Parent
 		}
 		b.Size--
+		b.version++
+		b.retrace(node.Parent)
 		return nil
 	}
 
 	// Case 3: Node has two children.
 	successor := minNode(node.Right)
 	node.Value = successor.Value   // Transfer the value from successor to node.
+	node.count = successor.count   // And all its copies in multiset mode.
 	return b.deleteNode(successor) // Recursively delete the successor, which will have 0 or 1 children.
 }
 
-// Delete a node with the given value from the BST.
+// Delete a node with the given value from the BST. In multiset mode, only one copy of the value
+// is deleted, the node is removed with the last one.
 func (b *BST) Delete(value int) error {
 	if b.Root == nil {
 		return fmt.Errorf("Delete error: tree is empty")
@@ -483,18 +598,61 @@ func (b *BST) Delete(value int) error {
 		return fmt.Errorf("Delete error: did not find value %d", value)
 	}
 
+	if copies(current) > 1 {
+		current.count--
+		b.Size--
+		b.version++
+		b.retrace(current)
+		return nil
+	}
+
 	// Use the helper function to delete the node.
 	return b.deleteNode(current)
 }
 
+// Get a deep copy of the tree, with the same shape and mode.
+func (b *BST) Clone() *BST {
+	var clone func(n *Node, parent *Node) *Node
+	clone = func(n *Node, parent *Node) *Node {
+		if n == nil {
+			return nil
+		}
+		c := *n
+		c.Parent = parent
+		c.Left = clone(n.Left, &c)
+		c.Right = clone(n.Right, &c)
+		return &c
+	}
+	return &BST{Root: clone(b.Root, nil), Size: b.Size, avl: b.avl, multiset: b.multiset}
+}
+
+// Get the values of the nodes in increasing order, and the number of copies of each one.
+func (b *BST) valueCounts() ([]int, []uint) {
+	values, counts := []int{}, []uint{}
+	var collect func(n *Node)
+	collect = func(n *Node) {
+		if n == nil {
+			return
+		}
+		collect(n.Left)
+		values = append(values, n.Value)
+		counts = append(counts, copies(n))
+		collect(n.Right)
+	}
+	collect(b.Root)
+	return values, counts
+}
+
 // Rebalance the tree using in-order traversal, and then creating a new, balanced tree.
 func (b *BST) Rebalance() {
-	values := b.InOrderIterative() // Already sorted.
-	b.Root = newFromSlice(values, nil)
+	values, counts := b.valueCounts() // Already sorted.
+	b.Root = newFromCounts(values, counts, nil)
+	b.version++
 	// Size remains the same, no need to modify b.Size.
 }
 
-// Rotate the tree clockwise around the node, call it node A.
+// Rotate the tree clockwise around the node, call it node A. Works on the nodes of both BST and
+// TreeMap, see avlNode.
 // The left child of A (node B) becomes the new root, and A becomes the right child of B.
 // The right child of B (node E) becomes the left child of A.
 //
@@ -503,33 +661,38 @@ func (b *BST) Rebalance() {
 //	  B   C  ---->  D   A
 //	 / \               / \
 //	D   E             E   C
-func (n *Node) rightRotation() *Node {
+func rightRotation[N avlNode[N]](n N) N {
+	var none N
 
 	// Select B as the new root and connect it to the upper side of the tree.
-	newRoot := n.Left
-	newRoot.Parent = n.Parent
-	if n.Parent != nil { // Check if A was left or right child and plug B into that slot instead.
-		if n.Parent.Left == n {
-			n.Parent.Left = newRoot
+	newRoot := n.left()
+	newRoot.setParent(n.parent())
+	if n.parent() != none { // Check if A was left or right child and plug B into that slot instead.
+		if n.parent().left() == n {
+			n.parent().setLeft(newRoot)
 		} else {
-			n.Parent.Right = newRoot
+			n.parent().setRight(newRoot)
 		}
 	}
 
 	// Connect E as a left child of A, if E is not nil, then update E's parent as well.
-	n.Left = newRoot.Right
-	if newRoot.Right != nil {
-		newRoot.Right.Parent = n
+	n.setLeft(newRoot.right())
+	if newRoot.right() != none {
+		newRoot.right().setParent(n)
 	}
 
 	// Move A to the right child of B.
-	newRoot.Right = n
-	n.Parent = newRoot
+	newRoot.setRight(n)
+	n.setParent(newRoot)
 
+	// A is now below B, so its cached values have to be updated first.
+	n.update()
+	newRoot.update()
 	return newRoot
 }
 
-// Rotate the tree counter-clockwise around the node, call it node A.
+// Rotate the tree counter-clockwise around the node, call it node A. Works on the nodes of both BST
+// and TreeMap, see avlNode.
 // The right child of A (node C) becomes the new root, and A becomes the left child of C.
 // The left child of C (node D) becomes the right child of A.
 //
@@ -538,28 +701,33 @@ func (n *Node) rightRotation() *Node {
 //	B   C    ---->    A   E
 //	   / \           / \
 //	  D   E         B   D
-func (n *Node) leftRotation() *Node {
+func leftRotation[N avlNode[N]](n N) N {
+	var none N
+
 	// Select C as the new root and connect it to the upper side of the tree.
-	newRoot := n.Right
-	newRoot.Parent = n.Parent
-	if n.Parent != nil { // Check if A was left or right child and plug C into that slot instead.
-		if n.Parent.Left == n {
-			n.Parent.Left = newRoot
+	newRoot := n.right()
+	newRoot.setParent(n.parent())
+	if n.parent() != none { // Check if A was left or right child and plug C into that slot instead.
+		if n.parent().left() == n {
+			n.parent().setLeft(newRoot)
 		} else {
-			n.Parent.Right = newRoot
+			n.parent().setRight(newRoot)
 		}
 	}
 
-	// Connect D as a right child of A, if E is not nil, then update E's parent as well.
-	n.Right = newRoot.Left
-	if newRoot.Left != nil {
-		newRoot.Left.Parent = n
+	// Connect D as a right child of A, if D is not nil, then update D's parent as well.
+	n.setRight(newRoot.left())
+	if newRoot.left() != none {
+		newRoot.left().setParent(n)
 	}
 
-	// Move A to the right child of C.
-	newRoot.Left = n
-	n.Parent = newRoot
+	// Move A to the left child of C.
+	newRoot.setLeft(n)
+	n.setParent(newRoot)
 
+	// A is now below C, so its cached values have to be updated first.
+	n.update()
+	newRoot.update()
 	return newRoot
 }
 
@@ -570,10 +738,12 @@ func (n *Node) leftRotation() *Node {
 func (b *BST) RebalanceDSW() {
 	// First step is converting the tree into a list, where every node has only the right child.
 	// We keep moving to the left child if it exists, perform a right rotation, and then move to the right child.
+	nodes := 0 // Not the same as b.Size in multiset mode.
 	current := b.Root
 	for current != nil {
+		nodes++
 		for current.Left != nil {
-			current = current.rightRotation()
+			current = rightRotation(current)
 			if current.Parent == nil {
 				// This is to ensure the root of the vine is correct. rightRotation already takes care of reassigning
 				// parents, so if after a rotation a node has no parent, it has to be the root of the tree.
@@ -585,16 +755,16 @@ func (b *BST) RebalanceDSW() {
 	// At this point, b is a tree with only right children, effectively it's a tree degenerated to a list.
 
 	// Calculate the number of initial rotations that have to be performed.
-	// This number has the following interpretation: if a perfect tree can be built from b.Size nodes this number is 0.
+	// This number has the following interpretation: if a perfect tree can be built from the nodes this number is 0.
 	// Otherwise this number is equal to the number of leaves in the last level of the tree.
-	// For instance, if b.Size == 5, then numInitialRotations == 2, if b.Size == 7, then numInitialRotations == 0.
-	numInitialRotations := int(b.Size) - int(math.Pow(2, math.Floor(math.Log2(float64(b.Size+1))))) + 1
+	// For instance, if nodes == 5, then numInitialRotations == 2, if nodes == 7, then numInitialRotations == 0.
+	numInitialRotations := nodes - int(math.Pow(2, math.Floor(math.Log2(float64(nodes+1))))) + 1
 
 	// Perform initial rotations starting from the root.
 	// This step ensures the bottom level is filled left to right.
 	current = b.Root
 	for i := 0; i < numInitialRotations; i++ {
-		current = current.leftRotation()
+		current = leftRotation(current)
 		if current.Parent == nil {
 			b.Root = current
 		}
@@ -604,10 +774,10 @@ func (b *BST) RebalanceDSW() {
 	}
 
 	// Balance the tree by performing left rotations, jumping every two nodes on the vine.
-	for m := int(b.Size) - numInitialRotations; m > 1; m /= 2 {
+	for m := nodes - numInitialRotations; m > 1; m /= 2 {
 		current = b.Root
 		for i := 0; i < m/2; i++ {
-			current = current.leftRotation()
+			current = leftRotation(current)
 			if current.Parent == nil {
 				b.Root = current
 			}
@@ -616,6 +786,20 @@ func (b *BST) RebalanceDSW() {
 			}
 		}
 	}
+
+	// Rotations only update the heights of the rotated nodes, not of their ancestors.
+	updateSubtree(b.Root)
+	b.version++
+}
+
+// Recompute the cached heights and sizes of all nodes in the subtree, children before parents.
+func updateSubtree(n *Node) {
+	if n == nil {
+		return
+	}
+	updateSubtree(n.Left)
+	updateSubtree(n.Right)
+	n.update()
 }
 
 // Helper recursive function to print the tree.
@@ -630,10 +814,14 @@ func printTree(node *Node, prefix string, isTail bool) {
 		printTree(node.Right, newPrefix, false)
 	}
 	fmt.Print(prefix)
+	label := fmt.Sprint(node.Value)
+	if copies(node) > 1 {
+		label += fmt.Sprintf(" (x%d)", copies(node)) // Copies in multiset mode.
+	}
 	if isTail {
-		fmt.Printf("└── %d\n", node.Value)
+		fmt.Printf("└── %s\n", label)
 	} else {
-		fmt.Printf("┌── %d\n", node.Value)
+		fmt.Printf("┌── %s\n", label)
 	}
 	if node.Left != nil {
 		newPrefix := prefix
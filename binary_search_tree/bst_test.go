@@ -360,3 +360,41 @@ func TestRebalanceDSW(t *testing.T) {
 		t.Errorf("Tree in-order traversal does not match the sorted values: %v", values4)
 	}
 }
+
+// TestHandBuiltTree tests a tree built from Node literals, without the cached values set.
+func TestHandBuiltTree(t *testing.T) {
+	root := &Node{Value: 5}
+	root.Left = &Node{Value: 3, Parent: root}
+	bst := &BST{Root: root, Size: 2}
+
+	if got := bst.InOrderIterative(); !reflect.DeepEqual(got, []int{3, 5}) {
+		t.Errorf("Expected in-order [3 5], got %v", got)
+	}
+	if got, err := bst.Select(0); err != nil || got != 3 {
+		t.Errorf("Expected Select(0) to be 3, got %d, %v", got, err)
+	}
+	if bst.Rank(5) != 1 || bst.Count(5) != 1 {
+		t.Errorf("Expected Rank(5) 1 and Count(5) 1, got %d and %d", bst.Rank(5), bst.Count(5))
+	}
+	if err := bst.Insert(4); err != nil {
+		t.Fatalf("Insert returned error: %v", err)
+	}
+	if got := bst.InOrderIterative(); !reflect.DeepEqual(got, []int{3, 4, 5}) || bst.Size != 3 {
+		t.Errorf("Expected [3 4 5] with size 3 after Insert(4), got %v with size %d", got, bst.Size)
+	}
+	checkTree(t, bst)
+
+	// Hand-built nodes in AVL mode are rebalanced like any other. The left subtree is not on the
+	// path of the inserted values, so its height has to be computed when the root is retraced.
+	root = &Node{Value: 5}
+	root.Left = &Node{Value: 3, Parent: root}
+	root.Left.Left = &Node{Value: 2, Parent: root.Left}
+	root.Left.Right = &Node{Value: 4, Parent: root.Left}
+	root.Right = &Node{Value: 6, Parent: root}
+	avl := &BST{Root: root, Size: 5, avl: true}
+	avl.Insert(7, 8)
+	checkTree(t, avl)
+	if avl.Root.Value != 5 || avl.Root.Right.Value != 7 {
+		t.Errorf("Expected 5 at the root and 7 as its right child, got %d and %d", avl.Root.Value, avl.Root.Right.Value)
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ============================
+// Encoding and decoding.
+// Trees are stored with their exact shape, so decoding gives back the same nodes in the same places,
+// not just the same values. Decoders check that the result is a valid BST (and a valid AVL tree in
+// AVL mode) and return an error otherwise, leaving the tree unchanged.
+// ============================
+
+// Flags of the binary format, the first byte holds the mode of the tree and every node starts
+// with a byte telling which of its optional parts follow.
+const (
+	binaryAVL      = 1 << 0
+	binaryMultiset = 1 << 1
+
+	binaryLeft  = 1 << 0
+	binaryRight = 1 << 1
+	binaryCount = 1 << 2
+)
+
+// Set up the parent pointers, cached heights and sizes of a decoded tree, check it, and use it
+// as the tree, in the given mode, if it's valid.
+func (b *BST) load(root *Node, avl bool, multiset bool) error {
+	// The values of the subtree of n must be in (lo, hi), nil means unbounded.
+	var check func(n *Node, lo *int, hi *int) error
+	check = func(n *Node, lo *int, hi *int) error {
+		if n == nil {
+			return nil
+		}
+		if lo != nil && n.Value <= *lo || hi != nil && n.Value >= *hi {
+			return fmt.Errorf("node %d is out of order", n.Value)
+		}
+		if n.count == 0 || n.count > 1 && !multiset {
+			return fmt.Errorf("node %d has %d copies", n.Value, n.count)
+		}
+		for _, child := range []*Node{n.Left, n.Right} {
+			if child != nil {
+				child.Parent = n
+			}
+		}
+		if err := check(n.Left, lo, &n.Value); err != nil {
+			return err
+		}
+		if err := check(n.Right, &n.Value, hi); err != nil {
+			return err
+		}
+		n.update()
+		if avl && (balanceFactor(n) > 1 || balanceFactor(n) < -1) {
+			return fmt.Errorf("node %d is not balanced", n.Value)
+		}
+		return nil
+	}
+	if err := check(root, nil, nil); err != nil {
+		return err
+	}
+	if root != nil {
+		root.Parent = nil
+	}
+	b.Root, b.Size, b.avl, b.multiset = root, nodeSize(root), avl, multiset
+	b.version++
+	return nil
+}
+
+// Encode the tree in a compact binary format, see UnmarshalBinary. The first byte holds the mode of
+// the tree. Then the nodes follow in pre-order, each one as a byte of flags (left child, right child,
+// more than one copy), the value as a varint and, in multiset mode, the number of copies as a uvarint
+// if it's more than 1. Small values take only 2 bytes per node. It implements encoding.BinaryMarshaler.
+func (b *BST) MarshalBinary() ([]byte, error) {
+	header := byte(0)
+	if b.avl {
+		header |= binaryAVL
+	}
+	if b.multiset {
+		header |= binaryMultiset
+	}
+	data := []byte{header}
+	var encode func(n *Node)
+	encode = func(n *Node) {
+		flags := byte(0)
+		if n.Left != nil {
+			flags |= binaryLeft
+		}
+		if n.Right != nil {
+			flags |= binaryRight
+		}
+		if copies(n) > 1 {
+			flags |= binaryCount
+		}
+		data = append(data, flags)
+		data = binary.AppendVarint(data, int64(n.Value))
+		if copies(n) > 1 {
+			data = binary.AppendUvarint(data, uint64(copies(n)))
+		}
+		if n.Left != nil {
+			encode(n.Left)
+		}
+		if n.Right != nil {
+			encode(n.Right)
+		}
+	}
+	if b.Root != nil {
+		encode(b.Root)
+	}
+	return data, nil
+}
+
+// Decode a tree encoded by MarshalBinary, replacing the contents and the mode of this tree.
+// Return an error if the data is malformed or the tree is not valid. It implements
+// encoding.BinaryUnmarshaler.
+func (b *BST) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("UnmarshalBinary error: data is empty")
+	}
+	header := data[0]
+	if header&^(binaryAVL|binaryMultiset) != 0 {
+		return fmt.Errorf("UnmarshalBinary error: unknown mode flags %#x", header)
+	}
+	pos := 1
+	var decode func() (*Node, error)
+	decode = func() (*Node, error) {
+		if pos >= len(data) {
+			return nil, fmt.Errorf("data ends at byte %d, in the middle of the tree", pos)
+		}
+		flags := data[pos]
+		if flags&^(binaryLeft|binaryRight|binaryCount) != 0 {
+			return nil, fmt.Errorf("unknown node flags %#x at byte %d", flags, pos)
+		}
+		pos++
+		value, read := binary.Varint(data[pos:])
+		if read <= 0 || int64(int(value)) != value {
+			return nil, fmt.Errorf("invalid value at byte %d", pos)
+		}
+		pos += read
+		n := &Node{Value: int(value), count: 1}
+		if flags&binaryCount != 0 {
+			count, read := binary.Uvarint(data[pos:])
+			if read <= 0 || count < 2 || uint64(uint(count)) != count {
+				return nil, fmt.Errorf("invalid count at byte %d", pos)
+			}
+			pos += read
+			n.count = uint(count)
+		}
+		var err error
+		if flags&binaryLeft != 0 {
+			if n.Left, err = decode(); err != nil {
+				return nil, err
+			}
+		}
+		if flags&binaryRight != 0 {
+			if n.Right, err = decode(); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	}
+
+	var root *Node
+	if len(data) > 1 {
+		var err error
+		if root, err = decode(); err != nil {
+			return fmt.Errorf("UnmarshalBinary error: %v", err)
+		}
+		if pos != len(data) {
+			return fmt.Errorf("UnmarshalBinary error: %d bytes left after the tree", len(data)-pos)
+		}
+	}
+	if err := b.load(root, header&binaryAVL != 0, header&binaryMultiset != 0); err != nil {
+		return fmt.Errorf("UnmarshalBinary error: %v", err)
+	}
+	return nil
+}
+
+// JSON form of a node, the number of copies is only written if it's more than 1.
+type jsonNode struct {
+	Value int       `json:"value"`
+	Count uint      `json:"count,omitempty"`
+	Left  *jsonNode `json:"left,omitempty"`
+	Right *jsonNode `json:"right,omitempty"`
+}
+
+// JSON form of a tree, the root is null for an empty tree.
+type jsonTree struct {
+	AVL      bool      `json:"avl,omitempty"`
+	Multiset bool      `json:"multiset,omitempty"`
+	Root     *jsonNode `json:"root"`
+}
+
+// Encode the tree as JSON, with nested nodes that have a value and optional left and right children:
+//
+//	{"root":{"value":5,"left":{"value":3},"right":{"value":8,"right":{"value":9}}}}
+//
+// The mode of the tree is stored in the "avl" and "multiset" fields if it's set, and the number of
+// copies of a value in the "count" field of its node if it's more than 1. It implements json.Marshaler,
+// so a BST can be part of other values passed to json.Marshal.
+func (b *BST) MarshalJSON() ([]byte, error) {
+	var convert func(n *Node) *jsonNode
+	convert = func(n *Node) *jsonNode {
+		if n == nil {
+			return nil
+		}
+		j := &jsonNode{Value: n.Value, Left: convert(n.Left), Right: convert(n.Right)}
+		if copies(n) > 1 {
+			j.Count = copies(n)
+		}
+		return j
+	}
+	return json.Marshal(jsonTree{AVL: b.avl, Multiset: b.multiset, Root: convert(b.Root)})
+}
+
+// Decode a tree encoded by MarshalJSON, replacing the contents and the mode of this tree.
+// Return an error if the JSON is malformed or the tree is not valid. It implements json.Unmarshaler.
+func (b *BST) UnmarshalJSON(data []byte) error {
+	var tree jsonTree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("UnmarshalJSON error: %v", err)
+	}
+	var convert func(j *jsonNode) *Node
+	convert = func(j *jsonNode) *Node {
+		if j == nil {
+			return nil
+		}
+		return &Node{Value: j.Value, count: max(j.Count, 1), Left: convert(j.Left), Right: convert(j.Right)}
+	}
+	if err := b.load(convert(tree.Root), tree.AVL, tree.Multiset); err != nil {
+		return fmt.Errorf("UnmarshalJSON error: %v", err)
+	}
+	return nil
+}
+
+// Encode the tree in level-order with null markers for missing children, as in LeetCode problems:
+// "[5,3,8,null,4]" is 5 with children 3 and 8, where 3 has no left child and the right child 4.
+// Trailing nulls are left out. Every node is a single value, so the mode of the tree and the number
+// of copies of values in multiset mode are not kept.
+func (b *BST) LevelOrderString() string {
+	tokens := []string{}
+	queue := NewQueue[*Node]()
+	queue.Enqueue(b.Root)
+	for queue.Length() > 0 {
+		current := queue.Dequeue()
+		if current == nil {
+			tokens = append(tokens, "null")
+			continue
+		}
+		tokens = append(tokens, strconv.Itoa(current.Value))
+		queue.Enqueue(current.Left)
+		queue.Enqueue(current.Right)
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1] == "null" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return "[" + strings.Join(tokens, ",") + "]"
+}
+
+// Get a new BST from its level-order form, see LevelOrderString. The values are assigned to the
+// children of the nodes in the order the nodes appear, and trailing nulls may be left out.
+// Return an error if the string is malformed or the tree is not a valid BST.
+func NewFromLevelOrderString(s string) (*BST, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("NewFromLevelOrderString error: expected a list in brackets, got %q", s)
+	}
+	var tokens []*Node // nil for null.
+	if inner := strings.TrimSpace(s[1 : len(s)-1]); inner != "" {
+		for _, token := range strings.Split(inner, ",") {
+			token = strings.TrimSpace(token)
+			if token == "null" {
+				tokens = append(tokens, nil)
+				continue
+			}
+			value, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, fmt.Errorf("NewFromLevelOrderString error: invalid value %q", token)
+			}
+			tokens = append(tokens, &Node{Value: value, count: 1})
+		}
+	}
+
+	var root *Node
+	if len(tokens) > 0 {
+		root = tokens[0]
+	}
+	// Every node in the queue takes the next two tokens as its children.
+	next := 1
+	queue := NewQueue[*Node]()
+	if root != nil {
+		queue.Enqueue(root)
+	}
+	for queue.Length() > 0 && next < len(tokens) {
+		current := queue.Dequeue()
+		for _, child := range []**Node{&current.Left, &current.Right} {
+			if next < len(tokens) {
+				*child = tokens[next]
+				if tokens[next] != nil {
+					queue.Enqueue(tokens[next])
+				}
+				next++
+			}
+		}
+	}
+	for _, token := range tokens[min(next, len(tokens)):] {
+		if token != nil {
+			return nil, fmt.Errorf("NewFromLevelOrderString error: value %d has no parent", token.Value)
+		}
+	}
+
+	b := NewEmpty()
+	if err := b.load(root, false, false); err != nil {
+		return nil, fmt.Errorf("NewFromLevelOrderString error: %v", err)
+	}
+	return b, nil
+}
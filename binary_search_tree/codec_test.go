@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Check that two trees have the same mode, and nodes with the same values and copies in the same places.
+func sameShape(a *BST, b *BST) bool {
+	var same func(x *Node, y *Node) bool
+	same = func(x *Node, y *Node) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Value == y.Value && x.count == y.count && same(x.Left, y.Left) && same(x.Right, y.Right)
+	}
+	return a.avl == b.avl && a.multiset == b.multiset && a.Size == b.Size && same(a.Root, b.Root)
+}
+
+// Trees of all modes and shapes, including degenerate ones and negative values.
+func codecTrees() []*BST {
+	r := rand.New(rand.NewSource(1))
+	trees := []*BST{NewEmpty(), NewFromSlice([]int{42}), NewAVL(), NewMultisetFromSlice([]int{-3, -3, 7, 1 << 40})}
+	list := NewEmpty()
+	for value := range 100 {
+		list.Insert(-value * 1000)
+	}
+	trees = append(trees, list)
+	for i := range 20 {
+		trees = append(trees, randomTree(r, r.Intn(200), 1000, i%2 == 0))
+		multiset := NewMultiset()
+		for range r.Intn(100) {
+			multiset.Insert(r.Intn(30) - 15)
+		}
+		trees = append(trees, multiset)
+	}
+	return trees
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, tree := range codecTrees() {
+		data, err := tree.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary returned error: %v", err)
+		}
+		decoded := NewEmpty()
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary returned error: %v", err)
+		}
+		checkTree(t, decoded)
+		if !sameShape(tree, decoded) {
+			t.Fatalf("UnmarshalBinary(MarshalBinary(%v)) has a different shape: %v", tree.PreOrderIterative(), decoded.PreOrderIterative())
+		}
+	}
+
+	// Every node with a value in [-64, 64) takes 2 bytes.
+	data, _ := NewFromSlice([]int{-2, -1, 0, 1, 2}).MarshalBinary()
+	if len(data) != 1+5*2 {
+		t.Errorf("Encoded 5 small values in %d bytes, want 11", len(data))
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	valid, _ := NewFromSlice([]int{1, 2, 3}).MarshalBinary()
+	outOfOrder, _ := NewFromSlice([]int{1, 2, 3}).MarshalBinary()
+	outOfOrder[2], outOfOrder[4] = outOfOrder[4], outOfOrder[2] // Swap the values of the root and its left child.
+	list := NewEmpty()
+	list.Insert(1, 2, 3)
+	unbalanced, _ := list.MarshalBinary()
+	unbalanced[0] = binaryAVL // A list of 3 nodes, but in AVL mode.
+	multiset, _ := NewMultisetFromSlice([]int{1, 1}).MarshalBinary()
+	multiset[0] = 0 // Two copies, but not in multiset mode.
+
+	tests := map[string][]byte{
+		"empty":               {},
+		"unknown mode":        {0x80},
+		"truncated":           valid[:len(valid)-1],
+		"trailing bytes":      append(append([]byte{}, valid...), 0),
+		"unknown node flags":  {0, 0x10, 2},
+		"out of order":        outOfOrder,
+		"unbalanced AVL":      unbalanced,
+		"copies in a set":     multiset,
+		"missing right child": {0, binaryRight, 2},
+	}
+	for name, data := range tests {
+		b := NewFromSlice([]int{10, 20})
+		if err := b.UnmarshalBinary(data); err == nil {
+			t.Errorf("%s: expected an error decoding %v", name, data)
+		}
+		if !slices.Equal(b.InOrderIterative(), []int{10, 20}) {
+			t.Errorf("%s: failed UnmarshalBinary modified the tree", name)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, tree := range codecTrees() {
+		data, err := json.Marshal(tree)
+		if err != nil {
+			t.Fatalf("json.Marshal returned error: %v", err)
+		}
+		decoded := NewEmpty()
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("json.Unmarshal returned error: %v", err)
+		}
+		checkTree(t, decoded)
+		if !sameShape(tree, decoded) {
+			t.Fatalf("JSON round trip of %s has a different shape: %v", data, decoded.PreOrderIterative())
+		}
+	}
+
+	bst := NewEmpty()
+	bst.Insert(5, 3, 8, 9)
+	data, _ := json.Marshal(bst)
+	if want := `{"root":{"value":5,"left":{"value":3},"right":{"value":8,"right":{"value":9}}}}`; string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+	data, _ = json.Marshal(NewMultisetFromSlice([]int{2, 2}))
+	if want := `{"multiset":true,"root":{"value":2,"count":2}}`; string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	tests := []string{
+		`{"root":{"value":5,"left":{"value":7}}}`,
+		`{"root":{"value":5,"right":{"value":7,"left":{"value":4}}}}`,
+		`{"root":{"value":5,"count":2}}`,
+		`{"avl":true,"root":{"value":1,"right":{"value":2,"right":{"value":3}}}}`,
+		`{"root":{"value":"5"}}`,
+		`[1,2,3]`,
+	}
+	for _, data := range tests {
+		b := NewEmpty()
+		if err := json.Unmarshal([]byte(data), b); err == nil {
+			t.Errorf("Expected an error decoding %s", data)
+		}
+	}
+}
+
+func TestLevelOrderString(t *testing.T) {
+	for _, tree := range codecTrees() {
+		if tree.multiset {
+			continue // Copies are not kept.
+		}
+		s := tree.LevelOrderString()
+		decoded, err := NewFromLevelOrderString(s)
+		if err != nil {
+			t.Fatalf("NewFromLevelOrderString(%s) returned error: %v", s, err)
+		}
+		checkTree(t, decoded)
+		tree.avl = false
+		if !sameShape(tree, decoded) {
+			t.Fatalf("NewFromLevelOrderString(%s) has a different shape: %v", s, decoded.PreOrderIterative())
+		}
+	}
+
+	bst := NewEmpty()
+	bst.Insert(5, 3, 8, 4)
+	if got, want := bst.LevelOrderString(), "[5,3,8,null,4]"; got != want {
+		t.Errorf("LevelOrderString() = %s, want %s", got, want)
+	}
+	if got := NewEmpty().LevelOrderString(); got != "[]" {
+		t.Errorf("LevelOrderString() of an empty tree = %s, want []", got)
+	}
+	decoded, err := NewFromLevelOrderString(" [5, 3, 8, null, 4, null, null, null, null] ")
+	if err != nil || decoded.Root.Left.Right.Value != 4 || decoded.Root.Left.Right.Parent != decoded.Root.Left {
+		t.Errorf("NewFromLevelOrderString with spaces and trailing nulls = %v, %v", decoded.PreOrderIterative(), err)
+	}
+
+	for _, s := range []string{"5,3", "[5,x]", "[5,6]", "[5,3,8,2,6]", "[null,1]", "[1,null,2,null,null,3]"} {
+		if _, err := NewFromLevelOrderString(s); err == nil {
+			t.Errorf("Expected an error decoding %s", s)
+		}
+	}
+}
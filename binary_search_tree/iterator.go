@@ -0,0 +1,229 @@
+package main
+
+import "iter"
+
+// ============================
+// Lazy iterators and cursors.
+// The iterators produce values one at a time while the caller ranges over them, instead of
+// building a slice of the whole tree first. Stopping the loop early stops the traversal.
+// ============================
+
+// Get the node with the smallest value greater than the value of n, using Parent pointers.
+// If n has a right subtree, it's the leftmost node there. Otherwise it's the first ancestor
+// that has n in its left subtree. Return nil if n is the last node.
+func nextNode(n *Node) *Node {
+	if n.Right != nil {
+		return minNode(n.Right)
+	}
+	for n.Parent != nil && n == n.Parent.Right {
+		n = n.Parent
+	}
+	return n.Parent
+}
+
+// Get the node with the largest value smaller than the value of n, the mirror image of nextNode.
+func prevNode(n *Node) *Node {
+	if n.Left != nil {
+		return maxNode(n.Left)
+	}
+	for n.Parent != nil && n == n.Parent.Left {
+		n = n.Parent
+	}
+	return n.Parent
+}
+
+// Bidirectional cursor over the values of a BST in sorted order. It steps between neighboring nodes
+// with Parent pointers, which takes O(1) amortized time per step.
+//
+// The tree may be modified while a cursor is in use. The next step then doesn't follow the pointers of
+// the cursor's node, which may no longer be in the tree, but searches the tree again for the closest
+// value after (or before) the cursor's value. So values inserted ahead of the cursor are visited,
+// deleted ones are not, and the cursor's own value stays valid even if it was deleted.
+type Cursor struct {
+	tree    *BST
+	node    *Node
+	value   int
+	version uint // Version of the tree when the cursor moved to the node.
+}
+
+// Get a cursor positioned at the smallest value greater than or equal to `value`. If there's no
+// such value, the cursor is not valid.
+//
+//	for c := bst.Cursor(10); c.Valid(); c.Next() {
+//		fmt.Println(c.Value())
+//	}
+func (b *BST) Cursor(value int) *Cursor {
+	c := &Cursor{tree: b}
+	c.moveTo(b.ceilingNode(value, true))
+	return c
+}
+
+func (c *Cursor) moveTo(n *Node) {
+	c.node = n
+	c.version = c.tree.version
+	if n != nil {
+		c.value = n.Value
+	}
+}
+
+// Check if the cursor is positioned at a value. Once it moves past either end of the tree,
+// it stays invalid.
+func (c *Cursor) Valid() bool {
+	return c.node != nil
+}
+
+// Get the value at the cursor, or 0 if the cursor is not valid.
+func (c *Cursor) Value() int {
+	if c.node == nil {
+		return 0
+	}
+	return c.value
+}
+
+// Get the number of copies of the value at the cursor, which is more than 1 only in multiset mode.
+// The cursor visits every value once. Return 0 if the cursor is not valid, or if its value was deleted.
+func (c *Cursor) Count() uint {
+	if c.node == nil {
+		return 0
+	}
+	if c.version != c.tree.version {
+		return c.tree.Count(c.value)
+	}
+	return copies(c.node)
+}
+
+// Move to the next larger value. Return false, and make the cursor invalid, if there's none.
+func (c *Cursor) Next() bool {
+	if c.node == nil {
+		return false
+	}
+	if c.version != c.tree.version {
+		c.moveTo(c.tree.ceilingNode(c.value, false))
+	} else {
+		c.moveTo(nextNode(c.node))
+	}
+	return c.node != nil
+}
+
+// Move to the next smaller value. Return false, and make the cursor invalid, if there's none.
+func (c *Cursor) Prev() bool {
+	if c.node == nil {
+		return false
+	}
+	if c.version != c.tree.version {
+		c.moveTo(c.tree.floorNode(c.value, false))
+	} else {
+		c.moveTo(prevNode(c.node))
+	}
+	return c.node != nil
+}
+
+// Get an iterator over the values in increasing order (left, node, right), with every copy of a value
+// in multiset mode, as in InOrderIterative. It's driven by a Cursor, so the tree may be modified during
+// the iteration: the values after the last produced one are always taken from the current tree.
+//
+//	for value := range bst.InOrderSeq() {
+//		fmt.Println(value)
+//	}
+func (b *BST) InOrderSeq() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if b.Root == nil {
+			return
+		}
+		c := &Cursor{tree: b}
+		for c.moveTo(minNode(b.Root)); c.Valid(); c.Next() {
+			for range c.Count() {
+				if !yield(c.Value()) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Get an iterator over the values in pre-order (node, left, right), see PreOrderIterative.
+// The tree must not be modified during the iteration, nodes that are moved may be skipped or repeated.
+func (b *BST) PreOrderSeq() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if b.Root == nil {
+			return
+		}
+		stack := NewStack[*Node]()
+		stack.Push(b.Root)
+		for stack.Length() > 0 {
+			node := stack.Pop()
+			if !yieldCopies(node, yield) {
+				return
+			}
+			// Push right child first so that the left child is processed first.
+			if node.Right != nil {
+				stack.Push(node.Right)
+			}
+			if node.Left != nil {
+				stack.Push(node.Left)
+			}
+		}
+	}
+}
+
+// Get an iterator over the values in post-order (left, right, node), see PostOrderIterative.
+// The tree must not be modified during the iteration, nodes that are moved may be skipped or repeated.
+func (b *BST) PostOrderSeq() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		stack := NewStack[*Node]()
+		var lastVisited *Node
+		current := b.Root
+		for stack.Length() > 0 || current != nil {
+			if current != nil {
+				stack.Push(current)
+				current = current.Left
+				continue
+			}
+			peek := stack.Peek()
+			// If right child exists and we came from the left child, then move right.
+			if peek.Right != nil && lastVisited != peek.Right {
+				current = peek.Right
+			} else {
+				if !yieldCopies(peek, yield) {
+					return
+				}
+				lastVisited = stack.Pop()
+			}
+		}
+	}
+}
+
+// Get an iterator over the values in level-order (breadth first), see LevelOrder.
+// The tree must not be modified during the iteration, nodes that are moved may be skipped or repeated.
+func (b *BST) LevelOrderSeq() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if b.Root == nil {
+			return
+		}
+		queue := NewQueue[*Node]()
+		queue.Enqueue(b.Root)
+		for queue.Length() > 0 {
+			current := queue.Dequeue()
+			if !yieldCopies(current, yield) {
+				return
+			}
+			if current.Left != nil {
+				queue.Enqueue(current.Left)
+			}
+			if current.Right != nil {
+				queue.Enqueue(current.Right)
+			}
+		}
+	}
+}
+
+// Yield the value of the node once for every copy of it, see appendCopies. Return false if `yield`
+// stopped the iteration.
+func yieldCopies(n *Node, yield func(int) bool) bool {
+	for range copies(n) {
+		if !yield(n.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSeqTraversals(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	trees := []*BST{NewEmpty(), NewFromSlice([]int{1}), NewFromSlice([]int{50, 20, 80, 10, 30, 70, 90})}
+	for range 20 {
+		bst := NewEmpty()
+		for _, value := range r.Perm(r.Intn(50)) {
+			bst.Insert(value)
+		}
+		trees = append(trees, bst)
+	}
+	for _, bst := range trees {
+		if got, want := collectValues(bst.InOrderSeq()), bst.InOrderIterative(); !slices.Equal(got, want) {
+			t.Errorf("InOrderSeq() = %v, want %v", got, want)
+		}
+		if got, want := collectValues(bst.PreOrderSeq()), bst.PreOrderIterative(); !slices.Equal(got, want) {
+			t.Errorf("PreOrderSeq() = %v, want %v", got, want)
+		}
+		if got, want := collectValues(bst.PostOrderSeq()), bst.PostOrderIterative(); !slices.Equal(got, want) {
+			t.Errorf("PostOrderSeq() = %v, want %v", got, want)
+		}
+		if got, want := collectValues(bst.LevelOrderSeq()), bst.LevelOrder(); !slices.Equal(got, want) {
+			t.Errorf("LevelOrderSeq() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSeqTraversalsStopEarly(t *testing.T) {
+	bst := NewFromSlice([]int{1, 2, 3, 4, 5, 6, 7})
+	tests := []struct {
+		name string
+		seq  func(yield func(int) bool)
+		want []int
+	}{
+		{"InOrderSeq", bst.InOrderSeq(), bst.InOrderIterative()},
+		{"PreOrderSeq", bst.PreOrderSeq(), bst.PreOrderIterative()},
+		{"PostOrderSeq", bst.PostOrderSeq(), bst.PostOrderIterative()},
+		{"LevelOrderSeq", bst.LevelOrderSeq(), bst.LevelOrder()},
+	}
+	for _, tt := range tests {
+		got := []int{}
+		for value := range tt.seq {
+			got = append(got, value)
+			if len(got) == 3 {
+				break
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want[:3]) {
+			t.Errorf("%s stopped after 3 values = %v, want %v", tt.name, got, tt.want[:3])
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	bst := NewFromSlice([]int{10, 20, 30, 40, 50, 60, 70})
+
+	// Walk forward from a value that is not in the tree, then all the way back.
+	c := bst.Cursor(25)
+	if !c.Valid() || c.Value() != 30 {
+		t.Fatalf("Cursor(25) at %d (valid %v), want 30", c.Value(), c.Valid())
+	}
+	forward := []int{}
+	for ; c.Valid(); c.Next() {
+		forward = append(forward, c.Value())
+	}
+	if want := []int{30, 40, 50, 60, 70}; !reflect.DeepEqual(forward, want) {
+		t.Errorf("Next from 25 visited %v, want %v", forward, want)
+	}
+	if c.Next() || c.Prev() || c.Value() != 0 {
+		t.Errorf("Cursor past the end should stay invalid")
+	}
+
+	backward := []int{}
+	for c := bst.Cursor(70); c.Valid(); c.Prev() {
+		backward = append(backward, c.Value())
+	}
+	if want := []int{70, 60, 50, 40, 30, 20, 10}; !reflect.DeepEqual(backward, want) {
+		t.Errorf("Prev from 70 visited %v, want %v", backward, want)
+	}
+
+	// Step back and forth.
+	c = bst.Cursor(40)
+	steps := []bool{c.Prev(), c.Prev(), c.Next(), c.Next(), c.Next()}
+	if !slices.Equal(steps, []bool{true, true, true, true, true}) || c.Value() != 50 {
+		t.Errorf("Cursor(40) after Prev, Prev, Next, Next, Next at %d, want 50", c.Value())
+	}
+
+	if c := bst.Cursor(71); c.Valid() {
+		t.Errorf("Cursor(71) should not be valid, at %d", c.Value())
+	}
+	if c := NewEmpty().Cursor(0); c.Valid() || c.Next() || c.Prev() {
+		t.Errorf("Cursor on an empty tree should not be valid")
+	}
+}
+
+func TestCursorModification(t *testing.T) {
+	bst := NewFromSlice([]int{10, 20, 30, 40, 50})
+	c := bst.Cursor(20)
+
+	// Delete the node under the cursor (which moves the value 30 into another node) and values ahead of it.
+	bst.Delete(20)
+	bst.Delete(40)
+	bst.Insert(45, 15)
+	got := []int{c.Value()}
+	for c.Next() {
+		got = append(got, c.Value())
+	}
+	if want := []int{20, 30, 45, 50}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cursor after modification visited %v, want %v", got, want)
+	}
+
+	c = bst.Cursor(45)
+	bst.Delete(45)
+	if !c.Prev() || c.Value() != 30 {
+		t.Errorf("Prev after deleting the cursor's value at %d, want 30", c.Value())
+	}
+
+	// The tree is modified on every step, including by rebalancing, which moves all the nodes.
+	bst = NewFromSlice([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	got = []int{}
+	for value := range bst.InOrderSeq() {
+		got = append(got, value)
+		if value%2 == 0 {
+			bst.Delete(value + 1)
+		} else if value < 100 {
+			bst.Insert(value + 100)
+		}
+		bst.Rebalance()
+	}
+	if want := []int{1, 2, 4, 6, 8, 101}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InOrderSeq with modifications visited %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+// ============================
+// Multiset mode.
+// A multiset keeps duplicates: inserting a value that is already in the tree adds a copy of it to its
+// node instead of returning an error. Size, the traversals and the order statistics count every copy.
+// ============================
+
+// Get a new empty BST in multiset mode.
+func NewMultiset() *BST {
+	return &BST{multiset: true}
+}
+
+// Get a new balanced BST in multiset mode from a slice, keeping all duplicates. The slice is not modified.
+func NewMultisetFromSlice(values []int) *BST {
+	distinct, counts := countValues(values)
+	return &BST{Root: newFromCounts(distinct, counts, nil), Size: uint(len(values)), multiset: true}
+}
+
+// Check if the tree keeps duplicates.
+func (b *BST) IsMultiset() bool {
+	return b.multiset
+}
+
+// Get the number of copies of the value in the tree, which is at most 1 outside of multiset mode.
+func (b *BST) Count(value int) uint {
+	current := b.Root
+	for current != nil {
+		if value < current.Value {
+			current = current.Left
+		} else if value > current.Value {
+			current = current.Right
+		} else {
+			return copies(current)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestNewFromSliceDuplicates(t *testing.T) {
+	values := []int{5, 3, 5, 1, 3, 5}
+	input := append([]int{}, values...)
+
+	set := NewFromSlice(values)
+	checkTree(t, set)
+	if got := set.InOrderIterative(); !reflect.DeepEqual(got, []int{1, 3, 5}) || set.Size != 3 {
+		t.Errorf("NewFromSlice(%v) has values %v and size %d, want [1 3 5] and 3", input, got, set.Size)
+	}
+
+	multiset := NewMultisetFromSlice(values)
+	checkTree(t, multiset)
+	if got := multiset.InOrderIterative(); !reflect.DeepEqual(got, []int{1, 3, 3, 5, 5, 5}) || multiset.Size != 6 {
+		t.Errorf("NewMultisetFromSlice(%v) has values %v and size %d, want [1 3 3 5 5 5] and 6", input, got, multiset.Size)
+	}
+	if !reflect.DeepEqual(values, input) {
+		t.Errorf("Building trees modified the slice to %v", values)
+	}
+	if set.IsMultiset() || !multiset.IsMultiset() {
+		t.Errorf("Expected only the second tree to be a multiset")
+	}
+}
+
+func TestMultiset_InsertDelete(t *testing.T) {
+	m := NewMultiset()
+	if err := m.Insert(4, 2, 4, 6, 4, 2); err != nil {
+		t.Fatalf("Insert returned error: %v", err)
+	}
+	checkTree(t, m)
+	if m.Size != 6 || m.Count(4) != 3 || m.Count(2) != 2 || m.Count(5) != 0 {
+		t.Errorf("Size %d, counts of 4, 2, 5: %d %d %d; want 6, 3 2 0", m.Size, m.Count(4), m.Count(2), m.Count(5))
+	}
+	for _, want := range []uint{2, 1, 0} {
+		if err := m.Delete(4); err != nil {
+			t.Fatalf("Delete(4) returned error: %v", err)
+		}
+		checkTree(t, m)
+		if m.Count(4) != want {
+			t.Errorf("After Delete(4), Count(4) = %d, want %d", m.Count(4), want)
+		}
+	}
+	if m.Contains(4) || m.Delete(4) == nil {
+		t.Errorf("Expected 4 to be gone after deleting all copies")
+	}
+	if got := m.InOrderIterative(); !reflect.DeepEqual(got, []int{2, 2, 6}) || m.Size != 3 {
+		t.Errorf("Values %v and size %d, want [2 2 6] and 3", got, m.Size)
+	}
+
+	// Outside of multiset mode duplicates are still an error.
+	if err := NewFromSlice([]int{1}).Insert(1); err == nil {
+		t.Errorf("Expected an error inserting a duplicate into a set")
+	}
+}
+
+func TestMultiset_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 20 {
+		m := NewMultiset()
+		want := []int{}
+		for range 300 {
+			value := r.Intn(20)
+			if r.Intn(3) > 0 {
+				m.Insert(value)
+				want = append(want, value)
+			} else if i := slices.Index(want, value); i >= 0 {
+				if err := m.Delete(value); err != nil {
+					t.Fatalf("Delete(%d) returned error: %v", value, err)
+				}
+				want = slices.Delete(want, i, i+1)
+			} else if m.Delete(value) == nil {
+				t.Fatalf("Delete(%d) of a missing value returned no error", value)
+			}
+		}
+		checkTree(t, m)
+		sort.Ints(want)
+		if got := m.InOrderIterative(); !slices.Equal(got, want) {
+			t.Fatalf("Values %v, want %v", got, want)
+		}
+		if got := collectValues(m.InOrderSeq()); !slices.Equal(got, want) {
+			t.Fatalf("InOrderSeq() = %v, want %v", got, want)
+		}
+		for k, value := range want {
+			if got, _ := m.Select(k); got != value {
+				t.Fatalf("Select(%d) = %d, want %d", k, got, value)
+			}
+		}
+		for value := -1; value <= 20; value++ {
+			if got, want := m.Rank(value), sort.SearchInts(want, value); got != want {
+				t.Fatalf("Rank(%d) = %d, want %d", value, got, want)
+			}
+		}
+		if got, want := collectValues(m.Range(5, 10)), want[sort.SearchInts(want, 5):sort.SearchInts(want, 11)]; !slices.Equal(got, want) {
+			t.Fatalf("Range(5, 10) = %v, want %v", got, want)
+		}
+
+		m.RebalanceDSW()
+		checkTree(t, m)
+		if !slices.Equal(m.InOrderIterative(), want) || !m.IsBalanced() {
+			t.Fatalf("RebalanceDSW changed the values or left the tree unbalanced")
+		}
+		m.Rebalance()
+		checkTree(t, m)
+		if !slices.Equal(m.InOrderIterative(), want) {
+			t.Fatalf("Rebalance changed the values")
+		}
+	}
+}
+
+func TestMultiset_Cursor(t *testing.T) {
+	m := NewMultisetFromSlice([]int{1, 3, 3, 3, 7})
+	c := m.Cursor(2)
+	if c.Value() != 3 || c.Count() != 3 {
+		t.Errorf("Cursor(2) at %d with %d copies, want 3 with 3", c.Value(), c.Count())
+	}
+	m.Delete(3)
+	if c.Count() != 2 {
+		t.Errorf("Count after deleting a copy = %d, want 2", c.Count())
+	}
+	if !c.Next() || c.Value() != 7 || c.Next() || c.Count() != 0 {
+		t.Errorf("Cursor should step to 7 and then past the end")
+	}
+}
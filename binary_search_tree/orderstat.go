@@ -0,0 +1,76 @@
+package main
+
+import "fmt"
+
+// ============================
+// Order statistics.
+// Every node caches the size of its subtree, so the position of a value in sorted order can be found
+// by going down from the root and counting the values in the skipped left subtrees, in O(height).
+// In multiset mode, every copy of a value has its own position.
+// ============================
+
+// Get the k-th smallest value in the tree, counting from 0, so Select(0) is the minimum.
+// Return an error if k is not in [0, Size).
+func (b *BST) Select(k int) (int, error) {
+	if k < 0 || k >= int(nodeSize(b.Root)) {
+		return 0, fmt.Errorf("Select error: k should be in range [0, %d), got %d", nodeSize(b.Root), k)
+	}
+	current := b.Root
+	for {
+		left := int(nodeSize(current.Left))
+		if k < left {
+			current = current.Left
+		} else if k >= left+int(copies(current)) {
+			k -= left + int(copies(current)) // Skip the left subtree and the current node.
+			current = current.Right
+		} else {
+			return current.Value, nil
+		}
+	}
+}
+
+// Get the number of values in the tree smaller than `value`, which doesn't have to be in the tree.
+// For a value in the tree, it's the position of the (first copy of the) value in sorted order,
+// so Select(Rank(x)) == x.
+func (b *BST) Rank(value int) int {
+	return b.countBelow(value, false)
+}
+
+// Get the number of values smaller than `value`, or smaller or equal if `inclusive`.
+func (b *BST) countBelow(value int, inclusive bool) int {
+	count := 0
+	current := b.Root
+	for current != nil {
+		if value < current.Value || value == current.Value && !inclusive {
+			current = current.Left
+		} else {
+			// The current node and its whole left subtree are below the value.
+			count += int(nodeSize(current.Left) + copies(current))
+			current = current.Right
+		}
+	}
+	return count
+}
+
+// Get the number of values in the range [lo, hi]. Return 0 if lo > hi.
+func (b *BST) CountInRange(lo int, hi int) int {
+	if lo > hi {
+		return 0
+	}
+	return b.countBelow(hi, true) - b.countBelow(lo, false)
+}
+
+// Get the k-th smallest value in the tree, see BST.Select.
+func (t *RBTree) Select(k int) (int, error) {
+	return t.view().Select(k)
+}
+
+// Get the number of values in the tree smaller than `value`, see BST.Rank.
+func (t *RBTree) Rank(value int) int {
+	return t.view().Rank(value)
+}
+
+// Get the number of values in the range [lo, hi], see BST.CountInRange.
+func (t *RBTree) CountInRange(lo int, hi int) int {
+	return t.view().CountInRange(lo, hi)
+}
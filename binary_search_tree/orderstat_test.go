@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestOrderStatistics(t *testing.T) {
+	bst := NewFromSlice([]int{50, 20, 80, 10, 30, 70, 90})
+	for k, expected := range []int{10, 20, 30, 50, 70, 80, 90} {
+		if value, err := bst.Select(k); err != nil || value != expected {
+			t.Errorf("Select(%d) = %d, %v; want %d", k, value, err, expected)
+		}
+		if rank := bst.Rank(expected); rank != k {
+			t.Errorf("Rank(%d) = %d, want %d", expected, rank, k)
+		}
+	}
+	if _, err := bst.Select(7); err == nil {
+		t.Errorf("Expected an error for Select(7) with 7 values")
+	}
+	if _, err := bst.Select(-1); err == nil {
+		t.Errorf("Expected an error for Select(-1)")
+	}
+	if _, err := NewEmpty().Select(0); err == nil {
+		t.Errorf("Expected an error for Select on an empty tree")
+	}
+
+	// Values that are not in the tree.
+	rankTests := []struct{ value, rank int }{{0, 0}, {25, 2}, {55, 4}, {100, 7}}
+	for _, tt := range rankTests {
+		if rank := bst.Rank(tt.value); rank != tt.rank {
+			t.Errorf("Rank(%d) = %d, want %d", tt.value, rank, tt.rank)
+		}
+	}
+
+	rangeTests := []struct{ lo, hi, count int }{
+		{20, 80, 5}, {21, 79, 3}, {0, 100, 7}, {60, 60, 0}, {50, 50, 1}, {80, 20, 0}, {math.MinInt, math.MaxInt, 7},
+	}
+	for _, tt := range rangeTests {
+		if count := bst.CountInRange(tt.lo, tt.hi); count != tt.count {
+			t.Errorf("CountInRange(%d, %d) = %d, want %d", tt.lo, tt.hi, count, tt.count)
+		}
+	}
+}
+
+// Random operations on all kinds of trees, including whole-tree rebalancing, compared with a sorted slice.
+func TestOrderStatisticsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(44))
+	trees := map[string]interface {
+		Insert(values ...int) error
+		Delete(value int) error
+		Select(k int) (int, error)
+		Rank(value int) int
+		CountInRange(lo int, hi int) int
+	}{
+		"plain": NewEmpty(),
+		"AVL":   NewAVL(),
+		"RB":    NewRBTree(),
+	}
+	for name, tree := range trees {
+		present := make(map[int]bool)
+		for i := 0; i < 2000; i++ {
+			value := r.Intn(100)
+			if r.Intn(3) == 0 {
+				tree.Delete(value)
+				delete(present, value)
+			} else {
+				tree.Insert(value)
+				present[value] = true
+			}
+			if bst, ok := tree.(*BST); ok && i%100 == 0 {
+				if r.Intn(2) == 0 {
+					bst.Rebalance()
+				} else {
+					bst.RebalanceDSW()
+				}
+			}
+
+			sorted := []int{}
+			for v := range present {
+				sorted = append(sorted, v)
+			}
+			sort.Ints(sorted)
+			k := r.Intn(len(sorted) + 1)
+			value, err := tree.Select(k)
+			if k < len(sorted) && (err != nil || value != sorted[k]) {
+				t.Fatalf("%v: Select(%d) = %d, %v; want %d", name, k, value, err, sorted[k])
+			}
+			if k == len(sorted) && err == nil {
+				t.Fatalf("%v: expected an error for Select(%d) with %d values", name, k, len(sorted))
+			}
+			x := r.Intn(110) - 5
+			if rank, expected := tree.Rank(x), sort.SearchInts(sorted, x); rank != expected {
+				t.Fatalf("%v: Rank(%d) = %d, want %d", name, x, rank, expected)
+			}
+			lo, hi := r.Intn(110)-5, r.Intn(110)-5
+			expected := 0
+			for _, v := range sorted {
+				if lo <= v && v <= hi {
+					expected++
+				}
+			}
+			if count := tree.CountInRange(lo, hi); count != expected {
+				t.Fatalf("%v: CountInRange(%d, %d) = %d, want %d", name, lo, hi, count, expected)
+			}
+		}
+		if bst, ok := tree.(*BST); ok {
+			checkTree(t, bst)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// ============================
+// Persistent tree.
+// Nodes of a persistent tree are never modified. Insert and Delete copy only the nodes on the path
+// from the root to the changed node, and the copies point to the same subtrees as the originals
+// for everything else. So every version of the tree stays valid and can still be queried, and a new
+// version costs O(log n) new nodes instead of the O(n) of cloning a BST.
+// ============================
+
+// Node of a PersistentBST. A node can be shared by many versions, so it has no Parent pointer.
+type persistentNode struct {
+	Value  int
+	Left   *persistentNode
+	Right  *persistentNode
+	height uint // Height of the subtree rooted at this node, a leaf has height 1.
+	size   uint // Number of nodes in the subtree rooted at this node.
+}
+
+// One version of a persistent BST, kept balanced as in AVL mode of BST. The zero value is an empty tree.
+type PersistentBST struct {
+	root *persistentNode
+}
+
+// Get a new empty persistent tree.
+func NewPersistent() *PersistentBST {
+	return &PersistentBST{}
+}
+
+// Get a new balanced persistent tree from a slice, which is not modified. Duplicates are ignored.
+func NewPersistentFromSlice(values []int) *PersistentBST {
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+	sorted = slices.Compact(sorted)
+	var build func(values []int) *persistentNode
+	build = func(values []int) *persistentNode {
+		if len(values) == 0 {
+			return nil
+		}
+		mid := len(values) / 2
+		return newPersistentNode(values[mid], build(values[:mid]), build(values[mid+1:]))
+	}
+	return &PersistentBST{root: build(sorted)}
+}
+
+func persistentHeight(n *persistentNode) uint {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func persistentSize(n *persistentNode) uint {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// Get a new node with the given children, which are shared, not copied.
+func newPersistentNode(value int, left *persistentNode, right *persistentNode) *persistentNode {
+	return &persistentNode{
+		Value:  value,
+		Left:   left,
+		Right:  right,
+		height: max(persistentHeight(left), persistentHeight(right)) + 1,
+		size:   persistentSize(left) + persistentSize(right) + 1,
+	}
+}
+
+// Get a new node with the given children, rotated if needed to restore the AVL property, see
+// rebalance. The children are balanced and their heights differ by at most 2. Instead of
+// rotating nodes in place, the rotated ones are copied.
+func balancePersistent(value int, left *persistentNode, right *persistentNode) *persistentNode {
+	switch factor := int(persistentHeight(left)) - int(persistentHeight(right)); {
+	case factor > 1:
+		if persistentHeight(left.Left) < persistentHeight(left.Right) {
+			// Left-right case, the right child of the left child becomes the root.
+			pivot := left.Right
+			return newPersistentNode(pivot.Value,
+				newPersistentNode(left.Value, left.Left, pivot.Left),
+				newPersistentNode(value, pivot.Right, right))
+		}
+		return newPersistentNode(left.Value, left.Left, newPersistentNode(value, left.Right, right))
+	case factor < -1:
+		if persistentHeight(right.Right) < persistentHeight(right.Left) {
+			// Right-left case, the left child of the right child becomes the root.
+			pivot := right.Left
+			return newPersistentNode(pivot.Value,
+				newPersistentNode(value, left, pivot.Left),
+				newPersistentNode(right.Value, pivot.Right, right.Right))
+		}
+		return newPersistentNode(right.Value, newPersistentNode(value, left, right.Left), right.Right)
+	}
+	return newPersistentNode(value, left, right)
+}
+
+// Get the number of values in this version of the tree.
+func (t *PersistentBST) Len() int {
+	return int(persistentSize(t.root))
+}
+
+// Get the height of this version of the tree.
+func (t *PersistentBST) Height() uint {
+	return persistentHeight(t.root)
+}
+
+// Get a new version of the tree with the values inserted, this version is not modified.
+// If a value already exists, return an error and no new version.
+func (t *PersistentBST) Insert(values ...int) (*PersistentBST, error) {
+	root := t.root
+	for _, value := range values {
+		var err error
+		if root, err = insertPersistent(root, value); err != nil {
+			return nil, err
+		}
+	}
+	return &PersistentBST{root: root}, nil
+}
+
+// Insert a value into the subtree and return the root of the new subtree.
+func insertPersistent(n *persistentNode, value int) (*persistentNode, error) {
+	if n == nil {
+		return newPersistentNode(value, nil, nil), nil
+	}
+	if value < n.Value {
+		left, err := insertPersistent(n.Left, value)
+		if err != nil {
+			return nil, err
+		}
+		return balancePersistent(n.Value, left, n.Right), nil
+	}
+	if value > n.Value {
+		right, err := insertPersistent(n.Right, value)
+		if err != nil {
+			return nil, err
+		}
+		return balancePersistent(n.Value, n.Left, right), nil
+	}
+	return nil, fmt.Errorf("Insert error: value %d already exists", value)
+}
+
+// Get a new version of the tree without the value, this version is not modified.
+// If the value is not in the tree, return an error and no new version.
+func (t *PersistentBST) Delete(value int) (*PersistentBST, error) {
+	if t.root == nil {
+		return nil, fmt.Errorf("Delete error: tree is empty")
+	}
+	root, err := deletePersistent(t.root, value)
+	if err != nil {
+		return nil, err
+	}
+	return &PersistentBST{root: root}, nil
+}
+
+// Delete a value from the subtree and return the root of the new subtree. As in BST, a node with
+// two children is replaced by its successor, which is deleted from the right subtree.
+func deletePersistent(n *persistentNode, value int) (*persistentNode, error) {
+	if n == nil {
+		return nil, fmt.Errorf("Delete error: did not find value %d", value)
+	}
+	if value < n.Value {
+		left, err := deletePersistent(n.Left, value)
+		if err != nil {
+			return nil, err
+		}
+		return balancePersistent(n.Value, left, n.Right), nil
+	}
+	if value > n.Value {
+		right, err := deletePersistent(n.Right, value)
+		if err != nil {
+			return nil, err
+		}
+		return balancePersistent(n.Value, n.Left, right), nil
+	}
+	if n.Left == nil {
+		return n.Right, nil
+	}
+	if n.Right == nil {
+		return n.Left, nil
+	}
+	successor := n.Right
+	for successor.Left != nil {
+		successor = successor.Left
+	}
+	right, _ := deletePersistent(n.Right, successor.Value) // The successor is there, so it can't fail.
+	return balancePersistent(successor.Value, n.Left, right), nil
+}
+
+// Check if the value exists in this version of the tree.
+func (t *PersistentBST) Contains(value int) bool {
+	current := t.root
+	for current != nil {
+		if value < current.Value {
+			current = current.Left
+		} else if value > current.Value {
+			current = current.Right
+		} else {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the minimum value in this version of the tree.
+func (t *PersistentBST) Min() (int, error) {
+	if t.root == nil {
+		return 0, fmt.Errorf("Min error: tree is empty")
+	}
+	current := t.root
+	for current.Left != nil {
+		current = current.Left
+	}
+	return current.Value, nil
+}
+
+// Get the maximum value in this version of the tree.
+func (t *PersistentBST) Max() (int, error) {
+	if t.root == nil {
+		return 0, fmt.Errorf("Max error: tree is empty")
+	}
+	current := t.root
+	for current.Right != nil {
+		current = current.Right
+	}
+	return current.Value, nil
+}
+
+// Get an iterator over the values of this version in increasing order. Other versions can be created
+// during the iteration, as they don't change this one.
+func (t *PersistentBST) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		var inOrder func(n *persistentNode) bool
+		inOrder = func(n *persistentNode) bool {
+			if n == nil {
+				return true
+			}
+			return inOrder(n.Left) && yield(n.Value) && inOrder(n.Right)
+		}
+		inOrder(t.root)
+	}
+}
+
+// In-order traversal (left, node, right) of this version.
+func (t *PersistentBST) InOrder() []int {
+	return slices.Collect(t.All())
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// Check the cached heights and sizes, the AVL property and the order of values of a persistent tree.
+func checkPersistent(t *testing.T, p *PersistentBST) {
+	t.Helper()
+	var check func(n *persistentNode) uint
+	check = func(n *persistentNode) uint {
+		if n == nil {
+			return 0
+		}
+		left, right := check(n.Left), check(n.Right)
+		if n.height != max(left, right)+1 {
+			t.Fatalf("Node %d has cached height %d, want %d", n.Value, n.height, max(left, right)+1)
+		}
+		if n.size != persistentSize(n.Left)+persistentSize(n.Right)+1 {
+			t.Fatalf("Node %d has cached size %d", n.Value, n.size)
+		}
+		if factor := int(left) - int(right); factor > 1 || factor < -1 {
+			t.Fatalf("Node %d is not balanced, heights %d and %d", n.Value, left, right)
+		}
+		return n.height
+	}
+	check(p.root)
+	values := p.InOrder()
+	for i := 1; i < len(values); i++ {
+		if values[i-1] >= values[i] {
+			t.Fatalf("Tree is not a valid BST: %v", values)
+		}
+	}
+}
+
+func TestPersistent_Versions(t *testing.T) {
+	v0 := NewPersistent()
+	v1, err := v0.Insert(5, 3, 8)
+	if err != nil {
+		t.Fatalf("Insert returned error: %v", err)
+	}
+	v2, _ := v1.Insert(4)
+	v3, err := v2.Delete(5)
+	if err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	versions := []struct {
+		tree *PersistentBST
+		want []int
+	}{
+		{v0, nil},
+		{v1, []int{3, 5, 8}},
+		{v2, []int{3, 4, 5, 8}},
+		{v3, []int{3, 4, 8}},
+	}
+	for i, v := range versions {
+		checkPersistent(t, v.tree)
+		if got := v.tree.InOrder(); !slices.Equal(got, v.want) || v.tree.Len() != len(v.want) {
+			t.Errorf("Version %d has values %v, want %v", i, got, v.want)
+		}
+	}
+	if !v2.Contains(5) || v3.Contains(5) {
+		t.Errorf("Deleting 5 from a new version should keep it in the old one")
+	}
+
+	// The right subtree is not on the path to 4, so it's shared by both versions.
+	if v1.root.Right != v2.root.Right {
+		t.Errorf("Inserting into the left subtree copied the right one")
+	}
+
+	if _, err := v1.Insert(3); err == nil {
+		t.Errorf("Expected an error inserting a duplicate")
+	}
+	if _, err := v1.Delete(7); err == nil {
+		t.Errorf("Expected an error deleting a missing value")
+	}
+	if _, err := v0.Delete(1); err == nil {
+		t.Errorf("Expected an error deleting from an empty tree")
+	}
+	if _, err := v0.Min(); err == nil {
+		t.Errorf("Expected an error getting the minimum of an empty tree")
+	}
+	if got, _ := v3.Min(); got != 3 {
+		t.Errorf("Min() = %d, want 3", got)
+	}
+	if got, _ := v3.Max(); got != 8 {
+		t.Errorf("Max() = %d, want 8", got)
+	}
+}
+
+func TestPersistent_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	versions := []*PersistentBST{NewPersistentFromSlice([]int{50, 10, 50, 30})}
+	contents := [][]int{{10, 30, 50}}
+	for range 1000 {
+		i := r.Intn(len(versions))
+		value := r.Intn(100)
+		want := slices.Clone(contents[i])
+		var next *PersistentBST
+		var err error
+		if k, found := slices.BinarySearch(want, value); found {
+			next, err = versions[i].Delete(value)
+			want = slices.Delete(want, k, k+1)
+		} else {
+			next, err = versions[i].Insert(value)
+			want = slices.Insert(want, k, value)
+		}
+		if err != nil {
+			t.Fatalf("Modifying version %d by %d returned error: %v", i, value, err)
+		}
+		versions = append(versions, next)
+		contents = append(contents, want)
+	}
+	// All versions, old and new, still have their own values.
+	for i, v := range versions {
+		checkPersistent(t, v)
+		if got := v.InOrder(); !slices.Equal(got, contents[i]) {
+			t.Fatalf("Version %d has values %v, want %v", i, got, contents[i])
+		}
+	}
+}
+
+func TestPersistent_Sorted(t *testing.T) {
+	p := NewPersistent()
+	for value := range 1000 {
+		p, _ = p.Insert(value)
+	}
+	checkPersistent(t, p)
+	for value := 0; value < 1000; value += 2 {
+		p, _ = p.Delete(value)
+	}
+	checkPersistent(t, p)
+	if p.Len() != 500 || !sort.IntsAreSorted(p.InOrder()) {
+		t.Errorf("Expected 500 odd values, got %d", p.Len())
+	}
+}
+
+func TestBST_Clone(t *testing.T) {
+	for _, tree := range codecTrees() {
+		clone := tree.Clone()
+		checkTree(t, clone)
+		if !sameShape(tree, clone) {
+			t.Fatalf("Clone of %v has a different shape", tree.PreOrderIterative())
+		}
+		if tree.Root != nil && clone.Root == tree.Root {
+			t.Fatalf("Clone shares nodes with the original")
+		}
+	}
+}
+
+// Benchmarks comparing the memory needed to keep the old version of a tree around after a change:
+// a persistent tree copies O(log n) nodes, a BST has to be cloned first, copying all of them.
+// Run with `go test -bench . -benchmem` to see the bytes and allocations per operation.
+
+const benchmarkTreeSize = 10000
+
+func BenchmarkPersistentInsert(b *testing.B) {
+	tree := NewPersistentFromSlice(benchmarkValues())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.Insert(2*(i%benchmarkTreeSize) + 1) // Odd values are not in the tree.
+	}
+}
+
+func BenchmarkCloneInsert(b *testing.B) {
+	tree := NewAVLFromSlice(benchmarkValues())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.Clone().Insert(2*(i%benchmarkTreeSize) + 1)
+	}
+}
+
+func BenchmarkPersistentDelete(b *testing.B) {
+	tree := NewPersistentFromSlice(benchmarkValues())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.Delete(2 * (i % benchmarkTreeSize))
+	}
+}
+
+func BenchmarkCloneDelete(b *testing.B) {
+	tree := NewAVLFromSlice(benchmarkValues())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.Clone().Delete(2 * (i % benchmarkTreeSize))
+	}
+}
+
+// Get the even values in [0, 2 * benchmarkTreeSize).
+func benchmarkValues() []int {
+	values := make([]int, benchmarkTreeSize)
+	for i := range values {
+		values[i] = 2 * i
+	}
+	return values
+}
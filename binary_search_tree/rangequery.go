@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Get the node with the largest value smaller than `value` (or equal to it, if `inclusive`), or nil.
+// Going down from the root, every node with a smaller value is a candidate, and a closer one can only
+// be in its right subtree.
+func (b *BST) floorNode(value int, inclusive bool) *Node {
+	var best *Node
+	current := b.Root
+	for current != nil {
+		if value > current.Value || inclusive && value == current.Value {
+			best = current
+			current = current.Right
+		} else {
+			current = current.Left
+		}
+	}
+	return best
+}
+
+// Get the node with the smallest value greater than `value` (or equal to it, if `inclusive`), or nil,
+// see floorNode.
+func (b *BST) ceilingNode(value int, inclusive bool) *Node {
+	var best *Node
+	current := b.Root
+	for current != nil {
+		if value < current.Value || inclusive && value == current.Value {
+			best = current
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+	return best
+}
+
+// Get the largest value in the tree that is less than or equal to x, which doesn't have to be in the tree.
+// Return an error if all values are greater than x.
+func (b *BST) Floor(x int) (int, error) {
+	best := b.floorNode(x, true)
+	if best == nil {
+		return 0, fmt.Errorf("Floor error: no value less than or equal to %d in the tree", x)
+	}
+	return best.Value, nil
+}
+
+// Get the smallest value in the tree that is greater than or equal to x, which doesn't have to be
+// in the tree, see Floor. Return an error if all values are less than x.
+func (b *BST) Ceiling(x int) (int, error) {
+	best := b.ceilingNode(x, true)
+	if best == nil {
+		return 0, fmt.Errorf("Ceiling error: no value greater than or equal to %d in the tree", x)
+	}
+	return best.Value, nil
+}
+
+// Get an iterator over the values in [lo, hi] in increasing order. Only the subtrees that can contain
+// such values are visited, so it takes O(height + number of values in the range). The tree must not
+// be modified during the iteration, see Cursor for that.
+//
+//	for value := range bst.Range(10, 20) {
+//		fmt.Println(value)
+//	}
+func (b *BST) Range(lo int, hi int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		rangeRecursive(b.Root, lo, hi, yield)
+	}
+}
+
+// In-order traversal of the values in [lo, hi]. Return false if `yield` stopped the iteration.
+func rangeRecursive(n *Node, lo int, hi int, yield func(int) bool) bool {
+	if n == nil {
+		return true
+	}
+	// Values in the left subtree are smaller than this one, so they can only be in range
+	// if this one is above lo. Same for the right subtree and hi.
+	if lo < n.Value && !rangeRecursive(n.Left, lo, hi, yield) {
+		return false
+	}
+	if lo <= n.Value && n.Value <= hi && !yieldCopies(n, yield) {
+		return false
+	}
+	if n.Value < hi {
+		return rangeRecursive(n.Right, lo, hi, yield)
+	}
+	return true
+}
+
+// Get the largest value less than or equal to x, see BST.Floor.
+func (t *RBTree) Floor(x int) (int, error) {
+	return t.view().Floor(x)
+}
+
+// Get the smallest value greater than or equal to x, see BST.Ceiling.
+func (t *RBTree) Ceiling(x int) (int, error) {
+	return t.view().Ceiling(x)
+}
+
+// Get an iterator over the values in [lo, hi] in increasing order, see BST.Range.
+func (t *RBTree) Range(lo int, hi int) iter.Seq[int] {
+	return t.view().Range(lo, hi)
+}
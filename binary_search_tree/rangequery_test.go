@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Collect the values produced by an iterator.
+func collectValues(seq func(yield func(int) bool)) []int {
+	values := []int{}
+	seq(func(value int) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+func TestFloorCeiling(t *testing.T) {
+	bst := NewFromSlice([]int{50, 20, 80, 10, 30, 70, 90})
+	tests := []struct {
+		x, floor, ceiling int
+		floorErr, ceilErr bool
+	}{
+		{x: 50, floor: 50, ceiling: 50},
+		{x: 25, floor: 20, ceiling: 30},
+		{x: 55, floor: 50, ceiling: 70},
+		{x: 85, floor: 80, ceiling: 90},
+		{x: 5, floorErr: true, ceiling: 10},
+		{x: 95, floor: 90, ceilErr: true},
+	}
+	for _, tt := range tests {
+		floor, err := bst.Floor(tt.x)
+		if (err != nil) != tt.floorErr || err == nil && floor != tt.floor {
+			t.Errorf("Floor(%d) = %d, %v; want %d", tt.x, floor, err, tt.floor)
+		}
+		ceiling, err := bst.Ceiling(tt.x)
+		if (err != nil) != tt.ceilErr || err == nil && ceiling != tt.ceiling {
+			t.Errorf("Ceiling(%d) = %d, %v; want %d", tt.x, ceiling, err, tt.ceiling)
+		}
+	}
+	if _, err := NewEmpty().Floor(1); err == nil {
+		t.Errorf("Expected an error for Floor on an empty tree")
+	}
+}
+
+func TestRange(t *testing.T) {
+	bst := NewFromSlice([]int{50, 20, 80, 10, 30, 70, 90})
+	if values := collectValues(bst.Range(20, 70)); !reflect.DeepEqual(values, []int{20, 30, 50, 70}) {
+		t.Errorf("Range(20, 70) = %v", values)
+	}
+	if values := collectValues(bst.Range(31, 49)); len(values) != 0 {
+		t.Errorf("Expected an empty range, got %v", values)
+	}
+	if values := collectValues(bst.Range(70, 20)); len(values) != 0 {
+		t.Errorf("Expected an empty range for lo > hi, got %v", values)
+	}
+
+	// Stop after the first two values.
+	values := []int{}
+	bst.Range(0, 100)(func(value int) bool {
+		values = append(values, value)
+		return len(values) < 2
+	})
+	if !reflect.DeepEqual(values, []int{10, 20}) {
+		t.Errorf("Expected the iteration to stop after [10 20], got %v", values)
+	}
+}
+
+func TestRangeQueriesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(45))
+	for i := 0; i < 200; i++ {
+		values := r.Perm(50)[:r.Intn(50)]
+		bst := NewEmpty()
+		bst.Insert(values...)
+		rb := NewRBTreeFromSlice(values)
+		sorted := append([]int{}, values...)
+		sort.Ints(sorted)
+
+		x := r.Intn(60) - 5
+		i := sort.SearchInts(sorted, x) // First index with sorted[i] >= x.
+		for _, tree := range []interface {
+			Floor(x int) (int, error)
+			Ceiling(x int) (int, error)
+		}{bst, rb} {
+			floor, err := tree.Floor(x)
+			if i < len(sorted) && sorted[i] == x {
+				if err != nil || floor != x {
+					t.Fatalf("Floor(%d) = %d, %v; want %d", x, floor, err, x)
+				}
+			} else if i == 0 && err == nil || i > 0 && (err != nil || floor != sorted[i-1]) {
+				t.Fatalf("Floor(%d) = %d, %v in %v", x, floor, err, sorted)
+			}
+			ceiling, err := tree.Ceiling(x)
+			if i == len(sorted) && err == nil || i < len(sorted) && (err != nil || ceiling != sorted[i]) {
+				t.Fatalf("Ceiling(%d) = %d, %v in %v", x, ceiling, err, sorted)
+			}
+		}
+
+		lo, hi := r.Intn(60)-5, r.Intn(60)-5
+		expected := []int{}
+		for _, v := range sorted {
+			if lo <= v && v <= hi {
+				expected = append(expected, v)
+			}
+		}
+		if got := collectValues(bst.Range(lo, hi)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Range(%d, %d) = %v, want %v", lo, hi, got, expected)
+		}
+		if got := collectValues(rb.Range(lo, hi)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("RBTree Range(%d, %d) = %v, want %v", lo, hi, got, expected)
+		}
+	}
+}
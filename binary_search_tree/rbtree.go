@@ -0,0 +1,388 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Red-black tree, a balanced BST with the same methods as BST. Every node is red or black, the root
+// is black, a red node has no red children, and every path from a node down to a missing child has
+// the same number of black nodes (the black height). So no path is more than twice as long as any
+// other, and the height stays O(log n). Compared to AVL mode, the balance is looser, but Insert and
+// Delete do at most 2 and 3 rotations, the rest of the fixes are only recoloring.
+//
+// Nodes are the same as in BST, so the traversals and other read-only methods are shared with it.
+type RBTree struct {
+	Root *Node
+	Size uint
+}
+
+// Get a new empty red-black tree.
+func NewRBTree() *RBTree {
+	return &RBTree{}
+}
+
+// Get a new red-black tree from a slice, duplicates are ignored. The values are sorted and built into
+// a balanced tree as in NewFromSlice. All its leaves are on the last two levels, so coloring the last
+// level red (unless it's the root) gives every path the same black height.
+func NewRBTreeFromSlice(values []int) *RBTree {
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+	sorted = slices.Compact(sorted)
+
+	t := &RBTree{Root: newFromSlice(sorted, nil), Size: uint(len(sorted))}
+	last := int(nodeHeight(t.Root)) - 1
+	var color func(n *Node, depth int)
+	color = func(n *Node, depth int) {
+		if n == nil {
+			return
+		}
+		n.red = depth == last && depth > 0
+		color(n.Left, depth+1)
+		color(n.Right, depth+1)
+	}
+	color(t.Root, 0)
+	return t
+}
+
+// Get a BST with the same nodes, to reuse its read-only methods.
+func (t *RBTree) view() *BST {
+	return &BST{Root: t.Root, Size: t.Size}
+}
+
+// Check if a node is red, missing children count as black.
+func isRed(n *Node) bool {
+	return n != nil && n.red
+}
+
+// Update the cached heights and sizes from node n up to the root.
+func updateUp(n *Node) {
+	for ; n != nil; n = n.Parent {
+		n.update()
+	}
+}
+
+// Rotate left around n, see leftRotation, keeping the root and the cached values above up to date.
+func (t *RBTree) rotateLeft(n *Node) {
+	newRoot := leftRotation(n)
+	if newRoot.Parent == nil {
+		t.Root = newRoot
+	}
+	updateUp(newRoot.Parent)
+}
+
+// Rotate right around n, see rightRotation, keeping the root and the cached values above up to date.
+func (t *RBTree) rotateRight(n *Node) {
+	newRoot := rightRotation(n)
+	if newRoot.Parent == nil {
+		t.Root = newRoot
+	}
+	updateUp(newRoot.Parent)
+}
+
+// Insert a value as a red leaf, then fix red-red violations going up. If the uncle of the new node is
+// red, the parent and the uncle become black and the grandparent red, which moves the problem two
+// levels up. If the uncle is black, one or two rotations at the grandparent fix it for good.
+// If the value already exists, return an error.
+func (t *RBTree) insertSingle(value int) error {
+	var parent *Node
+	current := t.Root
+	for current != nil {
+		parent = current
+		if value < current.Value {
+			current = current.Left
+		} else if value > current.Value {
+			current = current.Right
+		} else {
+			return fmt.Errorf("Insert error: value %d already exists", value)
+		}
+	}
+
+	node := &Node{Value: value, Parent: parent, height: 1, size: 1, count: 1, red: true}
+	if parent == nil {
+		t.Root = node
+	} else if value < parent.Value {
+		parent.Left = node
+	} else {
+		parent.Right = node
+	}
+	t.Size++
+	updateUp(parent)
+
+	for isRed(node.Parent) {
+		// The parent is red, so it's not the root and the grandparent exists.
+		parent := node.Parent
+		grandparent := parent.Parent
+		if parent == grandparent.Left {
+			if uncle := grandparent.Right; isRed(uncle) {
+				parent.red, uncle.red, grandparent.red = false, false, true
+				node = grandparent
+				continue
+			}
+			if node == parent.Right {
+				// Left-right case, turn it into the left-left case.
+				t.rotateLeft(parent)
+				node, parent = parent, node
+			}
+			parent.red, grandparent.red = false, true
+			t.rotateRight(grandparent)
+		} else {
+			if uncle := grandparent.Left; isRed(uncle) {
+				parent.red, uncle.red, grandparent.red = false, false, true
+				node = grandparent
+				continue
+			}
+			if node == parent.Left {
+				// Right-left case, turn it into the right-right case.
+				t.rotateRight(parent)
+				node, parent = parent, node
+			}
+			parent.red, grandparent.red = false, true
+			t.rotateLeft(grandparent)
+		}
+	}
+	t.Root.red = false
+	return nil
+}
+
+// Insert multiple values into the tree.
+func (t *RBTree) Insert(values ...int) error {
+	for _, value := range values {
+		if err := t.insertSingle(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete a node with the given value from the tree. A node with two children gets the value of its
+// successor, which is deleted instead, so the removed node has at most one child that takes its place.
+// Removing a red node doesn't change black heights. Removing a black one leaves its side one black
+// node short, which is fixed by deleteFixup.
+func (t *RBTree) Delete(value int) error {
+	if t.Root == nil {
+		return fmt.Errorf("Delete error: tree is empty")
+	}
+	node := t.Root
+	for node != nil && node.Value != value {
+		if value < node.Value {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	if node == nil {
+		return fmt.Errorf("Delete error: did not find value %d", value)
+	}
+
+	if node.Left != nil && node.Right != nil {
+		successor := minNode(node.Right)
+		node.Value, node.count = successor.Value, successor.count
+		node = successor
+	}
+
+	child := node.Left
+	if child == nil {
+		child = node.Right
+	}
+	parent := node.Parent
+	if child != nil {
+		child.Parent = parent
+	}
+	if parent == nil {
+		t.Root = child
+	} else if node == parent.Left {
+		parent.Left = child
+	} else {
+		parent.Right = child
+	}
+	t.Size--
+	updateUp(parent)
+
+	if !node.red {
+		t.deleteFixup(child, parent)
+	}
+	return nil
+}
+
+// Restore the black height after a black node was removed above x (which may be nil), whose parent is
+// `parent`. A red x simply becomes black. Otherwise, look at the sibling w, which must exist:
+//   - w is red: rotate it above the parent and recolor, so that x gets a black sibling.
+//   - w is black with two black children: make w red, both sides are now short, move up to the parent.
+//   - w is black with a red child on the far side: rotate w above the parent, the red child becomes
+//     black and fills the missing black node. If only the near child is red, rotate it above w first.
+func (t *RBTree) deleteFixup(x *Node, parent *Node) {
+	for x != t.Root && !isRed(x) {
+		if x == parent.Left {
+			w := parent.Right
+			if isRed(w) {
+				w.red, parent.red = false, true
+				t.rotateLeft(parent)
+				w = parent.Right
+			}
+			if !isRed(w.Left) && !isRed(w.Right) {
+				w.red = true
+				x, parent = parent, parent.Parent
+				continue
+			}
+			if !isRed(w.Right) {
+				w.Left.red, w.red = false, true
+				t.rotateRight(w)
+				w = parent.Right
+			}
+			w.red, parent.red, w.Right.red = parent.red, false, false
+			t.rotateLeft(parent)
+		} else {
+			w := parent.Left
+			if isRed(w) {
+				w.red, parent.red = false, true
+				t.rotateRight(parent)
+				w = parent.Left
+			}
+			if !isRed(w.Left) && !isRed(w.Right) {
+				w.red = true
+				x, parent = parent, parent.Parent
+				continue
+			}
+			if !isRed(w.Left) {
+				w.Right.red, w.red = false, true
+				t.rotateLeft(w)
+				w = parent.Left
+			}
+			w.red, parent.red, w.Left.red = parent.red, false, false
+			t.rotateRight(parent)
+		}
+		x = t.Root
+	}
+	if x != nil {
+		x.red = false
+	}
+}
+
+// Check if the value exists in the tree.
+func (t *RBTree) Contains(value int) bool {
+	return t.view().Contains(value)
+}
+
+// Get the minimum value in the tree.
+func (t *RBTree) Min() (int, error) {
+	return t.view().Min()
+}
+
+// Get the maximum value in the tree.
+func (t *RBTree) Max() (int, error) {
+	return t.view().Max()
+}
+
+// Get the height of the tree.
+func (t *RBTree) Height() uint {
+	return t.view().Height()
+}
+
+// Find the smallest value greater than the given value, which must be in the tree.
+func (t *RBTree) Successor(value int) (int, error) {
+	return t.view().Successor(value)
+}
+
+// Find the largest value smaller than the given value, which must be in the tree.
+func (t *RBTree) Predecessor(value int) (int, error) {
+	return t.view().Predecessor(value)
+}
+
+// In-order recursive traversal (left, node, right).
+func (t *RBTree) InOrderRecursive() []int {
+	return t.view().InOrderRecursive()
+}
+
+// Pre-order recursive traversal (node, left, right).
+func (t *RBTree) PreOrderRecursive() []int {
+	return t.view().PreOrderRecursive()
+}
+
+// Post-order recursive traversal (left, right, node).
+func (t *RBTree) PostOrderRecursive() []int {
+	return t.view().PostOrderRecursive()
+}
+
+// In-order iterative traversal (left, node, right).
+func (t *RBTree) InOrderIterative() []int {
+	return t.view().InOrderIterative()
+}
+
+// Pre-order iterative traversal (node, left, right).
+func (t *RBTree) PreOrderIterative() []int {
+	return t.view().PreOrderIterative()
+}
+
+// Post-order iterative traversal (left, right, node).
+func (t *RBTree) PostOrderIterative() []int {
+	return t.view().PostOrderIterative()
+}
+
+// Level-order traversal (breadth first).
+func (t *RBTree) LevelOrder() []int {
+	return t.view().LevelOrder()
+}
+
+// Print the whole tree.
+func (t *RBTree) Print() {
+	t.view().Print()
+}
+
+// Check all the red-black properties, the BST order and the parent pointers. Return an error
+// describing the first violation, or nil if the tree is a valid red-black tree.
+func (t *RBTree) CheckInvariants() error {
+	if isRed(t.Root) {
+		return fmt.Errorf("root %d is red", t.Root.Value)
+	}
+	if t.Root != nil && t.Root.Parent != nil {
+		return fmt.Errorf("root %d has a parent", t.Root.Value)
+	}
+
+	// Get the black height of the subtree, and check that all its values are in (lo, hi).
+	count := uint(0)
+	var check func(n *Node, lo *int, hi *int) (int, error)
+	check = func(n *Node, lo *int, hi *int) (int, error) {
+		if n == nil {
+			return 1, nil
+		}
+		count++
+		if lo != nil && n.Value <= *lo || hi != nil && n.Value >= *hi {
+			return 0, fmt.Errorf("node %d is out of order", n.Value)
+		}
+		for _, child := range []*Node{n.Left, n.Right} {
+			if child == nil {
+				continue
+			}
+			if child.Parent != n {
+				return 0, fmt.Errorf("node %d is a child of %d, but has a different parent", child.Value, n.Value)
+			}
+			if n.red && child.red {
+				return 0, fmt.Errorf("red node %d has a red child %d", n.Value, child.Value)
+			}
+		}
+		left, err := check(n.Left, lo, &n.Value)
+		if err != nil {
+			return 0, err
+		}
+		right, err := check(n.Right, &n.Value, hi)
+		if err != nil {
+			return 0, err
+		}
+		if left != right {
+			return 0, fmt.Errorf("node %d has black height %d on the left and %d on the right", n.Value, left, right)
+		}
+		if !n.red {
+			left++
+		}
+		return left, nil
+	}
+	if _, err := check(t.Root, nil, nil); err != nil {
+		return err
+	}
+	if count != t.Size {
+		return fmt.Errorf("tree has %d nodes, but Size is %d", count, t.Size)
+	}
+	return nil
+}
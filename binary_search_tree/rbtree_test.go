@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Check the red-black invariants and the cached heights and sizes of all nodes.
+func checkRBTree(t *testing.T, tree *RBTree) {
+	t.Helper()
+	if err := tree.CheckInvariants(); err != nil {
+		t.Fatalf("Invalid red-black tree: %v", err)
+	}
+	var check func(n *Node)
+	check = func(n *Node) {
+		if n == nil {
+			return
+		}
+		if n.height != height(n) {
+			t.Fatalf("Node %d has cached height %d, want %d", n.Value, n.height, height(n))
+		}
+		if n.size != uint(len(inOrderRecursive(n, nil))) {
+			t.Fatalf("Node %d has cached size %d, want %d", n.Value, n.size, len(inOrderRecursive(n, nil)))
+		}
+		check(n.Left)
+		check(n.Right)
+	}
+	check(tree.Root)
+}
+
+func TestRBTree_Basic(t *testing.T) {
+	tree := NewRBTree()
+	if err := tree.Insert(5, 3, 8, 1, 4, 7); err != nil {
+		t.Fatalf("Insert returned error: %v", err)
+	}
+	checkRBTree(t, tree)
+	if tree.Size != 6 {
+		t.Errorf("Expected size 6, got %d", tree.Size)
+	}
+	if err := tree.Insert(3); err == nil {
+		t.Errorf("Expected error when inserting duplicate, got nil")
+	}
+	if !tree.Contains(4) || tree.Contains(10) {
+		t.Errorf("Expected to contain 4 and not 10")
+	}
+
+	expected := []int{1, 3, 4, 5, 7, 8}
+	for name, result := range map[string][]int{
+		"InOrderRecursive": tree.InOrderRecursive(),
+		"InOrderIterative": tree.InOrderIterative(),
+	} {
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("%v: expected %v, got %v", name, expected, result)
+		}
+	}
+	if !reflect.DeepEqual(tree.PreOrderRecursive(), tree.PreOrderIterative()) {
+		t.Errorf("PreOrder mismatch: %v and %v", tree.PreOrderRecursive(), tree.PreOrderIterative())
+	}
+	if !reflect.DeepEqual(tree.PostOrderRecursive(), tree.PostOrderIterative()) {
+		t.Errorf("PostOrder mismatch: %v and %v", tree.PostOrderRecursive(), tree.PostOrderIterative())
+	}
+	if level := tree.LevelOrder(); len(level) != 6 || level[0] != tree.Root.Value {
+		t.Errorf("Unexpected level order %v", level)
+	}
+
+	if min, err := tree.Min(); err != nil || min != 1 {
+		t.Errorf("Expected min 1, got %d, error: %v", min, err)
+	}
+	if max, err := tree.Max(); err != nil || max != 8 {
+		t.Errorf("Expected max 8, got %d, error: %v", max, err)
+	}
+	if successor, err := tree.Successor(5); err != nil || successor != 7 {
+		t.Errorf("Expected successor 7, got %d, error: %v", successor, err)
+	}
+	if predecessor, err := tree.Predecessor(4); err != nil || predecessor != 3 {
+		t.Errorf("Expected predecessor 3, got %d, error: %v", predecessor, err)
+	}
+
+	if err := tree.Delete(5); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	checkRBTree(t, tree)
+	if tree.Contains(5) || tree.Size != 5 {
+		t.Errorf("Expected 5 to be deleted, got %v", tree.InOrderIterative())
+	}
+	if err := tree.Delete(100); err == nil {
+		t.Errorf("Expected error when deleting a non-existent value")
+	}
+
+	empty := NewRBTree()
+	if err := empty.Delete(1); err == nil {
+		t.Errorf("Expected error when deleting from an empty tree")
+	}
+	if _, err := empty.Min(); err == nil {
+		t.Errorf("Expected error for min of an empty tree")
+	}
+}
+
+func TestRBTree_InsertSorted(t *testing.T) {
+	tree := NewRBTree()
+	for value := 1; value <= 1000; value++ {
+		tree.Insert(value)
+	}
+	checkRBTree(t, tree)
+	// The height of a red-black tree is at most 2 log2(n + 1).
+	if limit := uint(2 * math.Log2(1001)); tree.Height() > limit {
+		t.Errorf("Expected height at most %d, got %d", limit, tree.Height())
+	}
+	for value := 1; value <= 1000; value += 2 {
+		tree.Delete(value)
+	}
+	checkRBTree(t, tree)
+}
+
+func TestRBTree_FromSlice(t *testing.T) {
+	for n := 0; n <= 40; n++ {
+		values := rand.New(rand.NewSource(int64(n))).Perm(n)
+		tree := NewRBTreeFromSlice(append(values, values...)) // Duplicates are ignored.
+		checkRBTree(t, tree)
+		if tree.Size != uint(n) {
+			t.Fatalf("Expected size %d, got %d", n, tree.Size)
+		}
+		// The tree must still be valid after modifications.
+		tree.Insert(n, n+1)
+		tree.Delete(0)
+		checkRBTree(t, tree)
+	}
+}
+
+// Random inserts and deletes, compared with a map, checking the invariants after every operation.
+func TestRBTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	tree := NewRBTree()
+	present := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		value := r.Intn(300)
+		if r.Intn(2) == 0 {
+			err := tree.Delete(value)
+			if (err == nil) != present[value] {
+				t.Fatalf("Delete(%d) returned %v, value present: %v", value, err, present[value])
+			}
+			delete(present, value)
+		} else {
+			err := tree.Insert(value)
+			if (err == nil) == present[value] {
+				t.Fatalf("Insert(%d) returned %v, value present: %v", value, err, present[value])
+			}
+			present[value] = true
+		}
+		checkRBTree(t, tree)
+	}
+
+	expected := []int{}
+	for value := range present {
+		expected = append(expected, value)
+	}
+	sort.Ints(expected)
+	if values := tree.InOrderRecursive(); !reflect.DeepEqual(values, expected) {
+		t.Fatalf("Expected values %v, got %v", expected, values)
+	}
+}
+
+// The checker must catch broken trees.
+func TestRBTree_CheckInvariants(t *testing.T) {
+	tree := NewRBTreeFromSlice([]int{1, 2, 3, 4, 5, 6, 7})
+	tree.Root.red = true
+	if tree.CheckInvariants() == nil {
+		t.Errorf("Expected an error for a red root")
+	}
+	tree.Root.red = false
+
+	tree.Root.Left.red = true
+	if tree.CheckInvariants() == nil {
+		t.Errorf("Expected an error for different black heights")
+	}
+	tree.Root.Left.Left.red = true
+	tree.Root.Left.Right.red = true
+	tree.Root.Right.red = true
+	tree.Root.Right.Left.red = true
+	tree.Root.Right.Right.red = true
+	if tree.CheckInvariants() == nil {
+		t.Errorf("Expected an error for a red node with red children")
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import "fmt"
+
+// ============================
+// Set operations.
+// Union, Intersection and Difference merge the sorted values of both trees in O(n + m) and build
+// a balanced result with newFromSlice. Split and Join move nodes between trees without copying them,
+// so they only touch the nodes along one path, in O(height).
+// ============================
+
+// Merge the sorted values of two trees. `combine` gets the number of copies of a value in b and in
+// other (0 if it's missing) and returns the number of copies in the result. The result has the mode
+// of b, so it has at most one copy of each value unless b is a multiset.
+func (b *BST) merge(other *BST, combine func(inB uint, inOther uint) uint) *BST {
+	values, counts := b.valueCounts()
+	otherValues, otherCounts := other.valueCounts()
+
+	result := &BST{avl: b.avl, multiset: b.multiset}
+	merged, mergedCounts := []int{}, []uint{}
+	add := func(value int, inB uint, inOther uint) {
+		count := combine(inB, inOther)
+		if !b.multiset {
+			count = min(count, 1)
+		}
+		if count > 0 {
+			merged = append(merged, value)
+			mergedCounts = append(mergedCounts, count)
+			result.Size += count
+		}
+	}
+	i, j := 0, 0
+	for i < len(values) || j < len(otherValues) {
+		switch {
+		case j == len(otherValues) || i < len(values) && values[i] < otherValues[j]:
+			add(values[i], counts[i], 0)
+			i++
+		case i == len(values) || otherValues[j] < values[i]:
+			add(otherValues[j], 0, otherCounts[j])
+			j++
+		default:
+			add(values[i], counts[i], otherCounts[j])
+			i++
+			j++
+		}
+	}
+	result.Root = newFromCounts(merged, mergedCounts, nil)
+	return result
+}
+
+// Get a new balanced tree with the values that are in either tree, in O(n + m). In multiset mode,
+// a value has as many copies as in the tree that has more of them.
+func (b *BST) Union(other *BST) *BST {
+	return b.merge(other, func(inB uint, inOther uint) uint { return max(inB, inOther) })
+}
+
+// Get a new balanced tree with the values that are in both trees, in O(n + m). In multiset mode,
+// a value has as many copies as in the tree that has fewer of them.
+func (b *BST) Intersection(other *BST) *BST {
+	return b.merge(other, func(inB uint, inOther uint) uint { return min(inB, inOther) })
+}
+
+// Get a new balanced tree with the values of this tree that are not in the other one, in O(n + m).
+// In multiset mode, every copy in the other tree removes one copy.
+func (b *BST) Difference(other *BST) *BST {
+	return b.merge(other, func(inB uint, inOther uint) uint {
+		if inB < inOther {
+			return 0
+		}
+		return inB - inOther
+	})
+}
+
+// Join two trees and a node that goes between them, all values of `left` must be smaller than the
+// value of `mid` and all values of `right` greater. Return the root of the joined tree.
+//
+// Outside of AVL mode `mid` simply becomes the root. In AVL mode, that would unbalance it if the
+// heights of the trees differ by more than 1. Then `mid` goes down the right side of the higher tree
+// (or the left side, if it's the right one) to the first node that is at most 1 higher than the lower
+// tree, takes its place and gets it as a child. The nodes above are rebalanced as after an insertion.
+// This takes O(difference of the heights).
+func joinNodes(left *Node, mid *Node, right *Node, avl bool) *Node {
+	mid.Parent = nil
+	leftHeight, rightHeight := nodeHeight(left), nodeHeight(right)
+	if !avl || max(leftHeight, rightHeight)-min(leftHeight, rightHeight) <= 1 {
+		mid.Left, mid.Right = left, right
+		if left != nil {
+			left.Parent = mid
+		}
+		if right != nil {
+			right.Parent = mid
+		}
+		mid.update()
+		return mid
+	}
+
+	t := &BST{avl: true}
+	var parent *Node
+	if leftHeight > rightHeight {
+		t.Root = left
+		current := left
+		for nodeHeight(current) > rightHeight+1 {
+			parent, current = current, current.Right
+		}
+		parent.Right = joinNodes(current, mid, right, false)
+	} else {
+		t.Root = right
+		current := right
+		for nodeHeight(current) > leftHeight+1 {
+			parent, current = current, current.Left
+		}
+		parent.Left = joinNodes(left, mid, current, false)
+	}
+	mid.Parent = parent
+	t.retrace(parent)
+	return t.Root
+}
+
+// Split the subtree of n into the nodes with values smaller than x and the rest, and return their roots.
+// Going down the path to x, every node goes to one side together with its subtree on the far side,
+// and the two sides are joined back together on the way up.
+func splitNode(n *Node, x int, avl bool) (*Node, *Node) {
+	if n == nil {
+		return nil, nil
+	}
+	left, right := n.Left, n.Right
+	if left != nil {
+		left.Parent = nil
+	}
+	if right != nil {
+		right.Parent = nil
+	}
+	if x <= n.Value {
+		smaller, rest := splitNode(left, x, avl)
+		return smaller, joinNodes(rest, n, right, avl)
+	}
+	smaller, rest := splitNode(right, x, avl)
+	return joinNodes(left, n, smaller, avl), rest
+}
+
+// Split the tree into a tree with the values smaller than x and a tree with the values greater than
+// or equal to x, in O(height). Both have the mode of this tree, which is left empty, as its nodes are
+// moved to the new trees. In AVL mode, both trees are balanced.
+func (b *BST) Split(x int) (*BST, *BST) {
+	smaller, rest := splitNode(b.Root, x, b.avl)
+	left := &BST{Root: smaller, Size: nodeSize(smaller), avl: b.avl, multiset: b.multiset}
+	right := &BST{Root: rest, Size: nodeSize(rest), avl: b.avl, multiset: b.multiset}
+	b.Root, b.Size = nil, 0
+	b.version++
+	return left, right
+}
+
+// Join two trees, where all values of `a` must be smaller than all values of `b`, into one tree
+// in O(height), the inverse of Split. The smallest node of `b` is removed from it and joins the trees,
+// see joinNodes. Both trees are left empty, as their nodes are moved to the new tree.
+// Return an error if the trees are not in the same mode, or if their values overlap.
+func Join(a *BST, b *BST) (*BST, error) {
+	if a.avl != b.avl || a.multiset != b.multiset {
+		return nil, fmt.Errorf("Join error: trees should be in the same mode")
+	}
+	result := &BST{avl: a.avl, multiset: a.multiset}
+	switch {
+	case a.Root == nil:
+		result.Root = b.Root
+	case b.Root == nil:
+		result.Root = a.Root
+	default:
+		largest, smallest := maxNode(a.Root), minNode(b.Root)
+		if largest.Value >= smallest.Value {
+			return nil, fmt.Errorf("Join error: values of the first tree should be smaller, got %d and %d", largest.Value, smallest.Value)
+		}
+		b.deleteNode(smallest) // It has no left child, so it's the node that is removed.
+		result.Root = joinNodes(a.Root, smallest, b.Root, a.avl)
+	}
+	result.Size = nodeSize(result.Root)
+	for _, t := range []*BST{a, b} {
+		t.Root, t.Size = nil, 0
+		t.version++
+	}
+	return result, nil
+}
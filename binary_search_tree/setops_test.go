@@ -0,0 +1,149 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// Get a random tree with values in [0, maxValue), in AVL mode or not, built by insertions.
+func randomTree(r *rand.Rand, size int, maxValue int, avl bool) *BST {
+	b := NewEmpty()
+	if avl {
+		b = NewAVL()
+	}
+	for range size {
+		b.Insert(r.Intn(maxValue))
+	}
+	return b
+}
+
+func TestSetOperations(t *testing.T) {
+	a := NewFromSlice([]int{1, 3, 5, 7, 9})
+	b := NewFromSlice([]int{3, 4, 5, 6})
+	tests := []struct {
+		name string
+		got  *BST
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 3, 4, 5, 6, 7, 9}},
+		{"Intersection", a.Intersection(b), []int{3, 5}},
+		{"Difference", a.Difference(b), []int{1, 7, 9}},
+		{"Difference", b.Difference(a), []int{4, 6}},
+		{"Union with empty", a.Union(NewEmpty()), []int{1, 3, 5, 7, 9}},
+		{"Intersection with empty", NewEmpty().Intersection(a), []int{}},
+	}
+	for _, tt := range tests {
+		checkTree(t, tt.got)
+		if got := tt.got.InOrderIterative(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+		if !tt.got.IsBalanced() {
+			t.Errorf("%s is not balanced", tt.name)
+		}
+	}
+	// The operands are not modified.
+	if !slices.Equal(a.InOrderIterative(), []int{1, 3, 5, 7, 9}) || !slices.Equal(b.InOrderIterative(), []int{3, 4, 5, 6}) {
+		t.Errorf("Set operations modified their operands")
+	}
+}
+
+func TestSetOperationsMultiset(t *testing.T) {
+	a := NewMultisetFromSlice([]int{1, 1, 2, 3, 3, 3})
+	b := NewMultisetFromSlice([]int{1, 3, 3, 4, 4})
+	tests := []struct {
+		name string
+		got  *BST
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 1, 2, 3, 3, 3, 4, 4}},
+		{"Intersection", a.Intersection(b), []int{1, 3, 3}},
+		{"Difference", a.Difference(b), []int{1, 2, 3}},
+		{"Set union with a multiset", NewFromSlice([]int{1, 5}).Union(b), []int{1, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		checkTree(t, tt.got)
+		if got := tt.got.InOrderIterative(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitJoin(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := range 200 {
+		avl := i%2 == 0
+		b := randomTree(r, r.Intn(100), 200, avl)
+		values := b.InOrderIterative()
+		x := r.Intn(220) - 10
+		height := b.Height()
+
+		left, right := b.Split(x)
+		checkTree(t, left)
+		checkTree(t, right)
+		k := sort.SearchInts(values, x)
+		if !slices.Equal(left.InOrderIterative(), values[:k]) || !slices.Equal(right.InOrderIterative(), values[k:]) {
+			t.Fatalf("Split(%d) of %v = %v and %v", x, values, left.InOrderIterative(), right.InOrderIterative())
+		}
+		if b.Root != nil || b.Size != 0 {
+			t.Fatalf("Split should leave the tree empty")
+		}
+		// Outside of AVL mode, the split trees are made of parts of the path, so they're not higher.
+		if !avl && (left.Height() > height || right.Height() > height) {
+			t.Fatalf("Split trees of heights %d and %d from a tree of height %d", left.Height(), right.Height(), height)
+		}
+
+		joined, err := Join(left, right)
+		if err != nil {
+			t.Fatalf("Join returned error: %v", err)
+		}
+		checkTree(t, joined)
+		if !slices.Equal(joined.InOrderIterative(), values) || joined.IsAVL() != avl {
+			t.Fatalf("Join after Split(%d) = %v, want %v", x, joined.InOrderIterative(), values)
+		}
+		if left.Root != nil || right.Root != nil {
+			t.Fatalf("Join should leave the trees empty")
+		}
+	}
+}
+
+func TestJoinAVLDifferentHeights(t *testing.T) {
+	for _, sizes := range [][2]int{{1, 100}, {100, 1}, {3, 1000}, {1000, 3}, {0, 10}, {10, 0}} {
+		a, b := NewAVL(), NewAVL()
+		for value := range sizes[0] {
+			a.Insert(value)
+		}
+		for value := range sizes[1] {
+			b.Insert(sizes[0] + value)
+		}
+		joined, err := Join(a, b)
+		if err != nil {
+			t.Fatalf("Join returned error: %v", err)
+		}
+		checkTree(t, joined)
+		if joined.Size != uint(sizes[0]+sizes[1]) {
+			t.Errorf("Join of trees of sizes %v has size %d", sizes, joined.Size)
+		}
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	if _, err := Join(NewFromSlice([]int{1, 5}), NewFromSlice([]int{5, 9})); err == nil {
+		t.Errorf("Expected an error joining overlapping trees")
+	}
+	if _, err := Join(NewFromSlice([]int{1}), NewAVLFromSlice([]int{5})); err == nil {
+		t.Errorf("Expected an error joining trees in different modes")
+	}
+
+	// Multisets are split between copies of different values, all copies of a value stay together.
+	m := NewMultisetFromSlice([]int{1, 2, 2, 3})
+	left, right := m.Split(2)
+	if !slices.Equal(left.InOrderIterative(), []int{1}) || !slices.Equal(right.InOrderIterative(), []int{2, 2, 3}) {
+		t.Errorf("Split(2) of a multiset = %v and %v", left.InOrderIterative(), right.InOrderIterative())
+	}
+	joined, err := Join(left, right)
+	if err != nil || joined.Size != 4 || !joined.IsMultiset() {
+		t.Errorf("Join of a split multiset = %v, %v", joined, err)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Node of a TreeMap, the generic counterpart of Node. It shares the AVL rebalancing with Node, see avlNode.
+type mapNode[K any, V any] struct {
+	Key    K
+	Value  V
+	Left   *mapNode[K, V]
+	Right  *mapNode[K, V]
+	Parent *mapNode[K, V]
+	height uint // Cached height of the subtree rooted at this node, a leaf has height 1.
+}
+
+// Ordered map from keys to values, stored as a BST of keys that is kept balanced as in AVL mode of BST,
+// so Put, Get and Delete are O(log n). Keys are compared with a comparison function, which returns
+// a negative number, zero or a positive number if a < b, a == b or a > b.
+type TreeMap[K any, V any] struct {
+	root    *mapNode[K, V]
+	size    int
+	compare func(a K, b K) int
+}
+
+// Get a new empty map for keys with a natural order, such as numbers and strings.
+func NewTreeMap[K cmp.Ordered, V any]() *TreeMap[K, V] {
+	return &TreeMap[K, V]{compare: cmp.Compare[K]}
+}
+
+// Get a new empty map with keys ordered by the `compare` function, eg. for struct keys.
+func NewTreeMapFunc[K any, V any](compare func(a K, b K) int) *TreeMap[K, V] {
+	return &TreeMap[K, V]{compare: compare}
+}
+
+// Get the number of keys in the map.
+func (m *TreeMap[K, V]) Len() int {
+	return m.size
+}
+
+func mapNodeHeight[K any, V any](n *mapNode[K, V]) uint {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *mapNode[K, V]) update() {
+	n.height = max(mapNodeHeight(n.Left), mapNodeHeight(n.Right)) + 1
+}
+
+func (n *mapNode[K, V]) left() *mapNode[K, V]            { return n.Left }
+func (n *mapNode[K, V]) right() *mapNode[K, V]           { return n.Right }
+func (n *mapNode[K, V]) parent() *mapNode[K, V]          { return n.Parent }
+func (n *mapNode[K, V]) setLeft(child *mapNode[K, V])    { n.Left = child }
+func (n *mapNode[K, V]) setRight(child *mapNode[K, V])   { n.Right = child }
+func (n *mapNode[K, V]) setParent(parent *mapNode[K, V]) { n.Parent = parent }
+func (n *mapNode[K, V]) subtreeHeight() uint             { return n.height }
+
+// Update heights and rebalance every node from n up to the root, with the same rotations as BST
+// in AVL mode, see avlNode.
+func (m *TreeMap[K, V]) retrace(n *mapNode[K, V]) {
+	for n != nil {
+		n = rebalance(n)
+		if n.Parent == nil {
+			m.root = n
+		}
+		n = n.Parent
+	}
+}
+
+// Find the node with the given key, or nil.
+func (m *TreeMap[K, V]) find(key K) *mapNode[K, V] {
+	current := m.root
+	for current != nil {
+		c := m.compare(key, current.Key)
+		if c < 0 {
+			current = current.Left
+		} else if c > 0 {
+			current = current.Right
+		} else {
+			return current
+		}
+	}
+	return nil
+}
+
+// Set the value for a key, replacing the old value if the key is already in the map.
+func (m *TreeMap[K, V]) Put(key K, value V) {
+	if m.root == nil {
+		m.root = &mapNode[K, V]{Key: key, Value: value, height: 1}
+		m.size = 1
+		return
+	}
+	current := m.root
+	for {
+		c := m.compare(key, current.Key)
+		if c == 0 {
+			current.Value = value
+			return
+		}
+		next := &current.Left
+		if c > 0 {
+			next = &current.Right
+		}
+		if *next == nil {
+			*next = &mapNode[K, V]{Key: key, Value: value, Parent: current, height: 1}
+			m.size++
+			m.retrace(current)
+			return
+		}
+		current = *next
+	}
+}
+
+// Get the value for a key, and whether the key is in the map.
+func (m *TreeMap[K, V]) Get(key K) (V, bool) {
+	if n := m.find(key); n != nil {
+		return n.Value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete a key from the map. Return false if the key was not in the map.
+// As in BST, a node with two children takes the key and value of its successor, which is removed instead.
+func (m *TreeMap[K, V]) Delete(key K) bool {
+	node := m.find(key)
+	if node == nil {
+		return false
+	}
+	if node.Left != nil && node.Right != nil {
+		successor := node.Right
+		for successor.Left != nil {
+			successor = successor.Left
+		}
+		node.Key, node.Value = successor.Key, successor.Value
+		node = successor
+	}
+
+	child := node.Left
+	if child == nil {
+		child = node.Right
+	}
+	if child != nil {
+		child.Parent = node.Parent
+	}
+	if node.Parent == nil {
+		m.root = child
+	} else if node == node.Parent.Left {
+		node.Parent.Left = child
+	} else {
+		node.Parent.Right = child
+	}
+	m.size--
+	m.retrace(node.Parent)
+	return true
+}
+
+// Get the largest key that is less than or equal to `key`, with its value.
+// The last return value is false if there's no such key.
+func (m *TreeMap[K, V]) Floor(key K) (K, V, bool) {
+	var best *mapNode[K, V]
+	current := m.root
+	for current != nil {
+		c := m.compare(key, current.Key)
+		if c == 0 {
+			return current.Key, current.Value, true
+		}
+		if c > 0 {
+			best = current // A candidate, but there may be a larger one on the right.
+			current = current.Right
+		} else {
+			current = current.Left
+		}
+	}
+	if best == nil {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, false
+	}
+	return best.Key, best.Value, true
+}
+
+// Get the smallest key that is greater than or equal to `key`, with its value.
+// The last return value is false if there's no such key.
+func (m *TreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *mapNode[K, V]
+	current := m.root
+	for current != nil {
+		c := m.compare(key, current.Key)
+		if c == 0 {
+			return current.Key, current.Value, true
+		}
+		if c < 0 {
+			best = current // A candidate, but there may be a smaller one on the left.
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+	if best == nil {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, false
+	}
+	return best.Key, best.Value, true
+}
+
+// Get an iterator over all keys and values, in increasing order of keys.
+// The map must not be modified during the iteration.
+//
+//	for key, value := range m.All() {
+//		fmt.Println(key, value)
+//	}
+func (m *TreeMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.inOrder(m.root, nil, nil, yield)
+	}
+}
+
+// Get an iterator over the keys in [lo, hi] and their values, in increasing order of keys, see All.
+// Only the subtrees that can contain such keys are visited.
+func (m *TreeMap[K, V]) Range(lo K, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.inOrder(m.root, &lo, &hi, yield)
+	}
+}
+
+// In-order traversal of the keys within the bounds, nil means unbounded. Return false if `yield`
+// stopped the iteration.
+func (m *TreeMap[K, V]) inOrder(n *mapNode[K, V], lo *K, hi *K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := lo == nil || m.compare(*lo, n.Key) <= 0
+	belowHi := hi == nil || m.compare(n.Key, *hi) <= 0
+	// Smaller keys can only be in range if this one is above the lower bound, and vice versa.
+	if aboveLo && !m.inOrder(n.Left, lo, hi, yield) {
+		return false
+	}
+	if aboveLo && belowHi && !yield(n.Key, n.Value) {
+		return false
+	}
+	if belowHi {
+		return m.inOrder(n.Right, lo, hi, yield)
+	}
+	return true
+}
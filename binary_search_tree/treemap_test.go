@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Check parent pointers, cached heights, the AVL balance and the order of keys of a TreeMap.
+func checkTreeMap[K any, V any](t *testing.T, m *TreeMap[K, V]) {
+	t.Helper()
+	count := 0
+	var check func(n *mapNode[K, V]) uint
+	check = func(n *mapNode[K, V]) uint {
+		if n == nil {
+			return 0
+		}
+		count++
+		for _, child := range []*mapNode[K, V]{n.Left, n.Right} {
+			if child != nil && child.Parent != n {
+				t.Fatalf("Node %v has a child %v with a different parent", n.Key, child.Key)
+			}
+		}
+		if n.Left != nil && m.compare(n.Left.Key, n.Key) >= 0 || n.Right != nil && m.compare(n.Right.Key, n.Key) <= 0 {
+			t.Fatalf("Node %v is out of order with its children", n.Key)
+		}
+		left, right := check(n.Left), check(n.Right)
+		if int(left)-int(right) > 1 || int(right)-int(left) > 1 {
+			t.Fatalf("Node %v is not balanced, heights %d and %d", n.Key, left, right)
+		}
+		if n.height != max(left, right)+1 {
+			t.Fatalf("Node %v has cached height %d, want %d", n.Key, n.height, max(left, right)+1)
+		}
+		return n.height
+	}
+	check(m.root)
+	if count != m.Len() {
+		t.Fatalf("Map has %d nodes, but Len is %d", count, m.Len())
+	}
+}
+
+// Collect the keys and values produced by an iterator.
+func collect[K any, V any](seq func(yield func(K, V) bool)) ([]K, []V) {
+	keys, values := []K{}, []V{}
+	seq(func(key K, value V) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	return keys, values
+}
+
+func TestTreeMap_Basic(t *testing.T) {
+	m := NewTreeMap[string, int]()
+	for i, word := range []string{"pear", "apple", "fig", "kiwi", "banana"} {
+		m.Put(word, i)
+	}
+	m.Put("fig", 100) // Replace.
+	checkTreeMap(t, m)
+
+	if value, ok := m.Get("fig"); !ok || value != 100 {
+		t.Errorf("Expected fig = 100, got %d, %v", value, ok)
+	}
+	if _, ok := m.Get("grape"); ok {
+		t.Errorf("Expected grape to be missing")
+	}
+	keys, values := collect(m.All())
+	if !reflect.DeepEqual(keys, []string{"apple", "banana", "fig", "kiwi", "pear"}) || !reflect.DeepEqual(values, []int{1, 4, 100, 3, 0}) {
+		t.Errorf("Unexpected iteration %v, %v", keys, values)
+	}
+
+	if key, _, ok := m.Floor("grape"); !ok || key != "fig" {
+		t.Errorf("Expected floor of grape to be fig, got %v, %v", key, ok)
+	}
+	if key, _, ok := m.Ceiling("grape"); !ok || key != "kiwi" {
+		t.Errorf("Expected ceiling of grape to be kiwi, got %v, %v", key, ok)
+	}
+	if key, _, ok := m.Floor("kiwi"); !ok || key != "kiwi" {
+		t.Errorf("Expected floor of kiwi to be kiwi, got %v, %v", key, ok)
+	}
+	if _, _, ok := m.Floor("aardvark"); ok {
+		t.Errorf("Expected no floor for aardvark")
+	}
+	if _, _, ok := m.Ceiling("zebra"); ok {
+		t.Errorf("Expected no ceiling for zebra")
+	}
+
+	if keys, _ := collect(m.Range("b", "kiwi")); !reflect.DeepEqual(keys, []string{"banana", "fig", "kiwi"}) {
+		t.Errorf("Unexpected range [b, kiwi]: %v", keys)
+	}
+	if keys, _ := collect(m.Range("q", "z")); len(keys) != 0 {
+		t.Errorf("Expected an empty range, got %v", keys)
+	}
+
+	// Stop early.
+	first := []string{}
+	m.All()(func(key string, _ int) bool {
+		first = append(first, key)
+		return len(first) < 2
+	})
+	if !reflect.DeepEqual(first, []string{"apple", "banana"}) {
+		t.Errorf("Expected iteration to stop after 2 keys, got %v", first)
+	}
+
+	if !m.Delete("apple") || m.Delete("apple") {
+		t.Errorf("Expected apple to be deleted exactly once")
+	}
+	checkTreeMap(t, m)
+	if m.Len() != 4 {
+		t.Errorf("Expected 4 keys, got %d", m.Len())
+	}
+}
+
+// A comparator-based map with struct keys, ordered by last name and then first name, ignoring case.
+func TestTreeMap_Func(t *testing.T) {
+	type person struct{ first, last string }
+	m := NewTreeMapFunc[person, int](func(a, b person) int {
+		if c := strings.Compare(strings.ToLower(a.last), strings.ToLower(b.last)); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.first), strings.ToLower(b.first))
+	})
+	m.Put(person{"Ada", "Lovelace"}, 1815)
+	m.Put(person{"Alan", "Turing"}, 1912)
+	m.Put(person{"Charles", "Babbage"}, 1791)
+	m.Put(person{"alan", "turing"}, 1912) // Same key.
+	checkTreeMap(t, m)
+
+	keys, _ := collect(m.All())
+	expected := []person{{"Charles", "Babbage"}, {"Ada", "Lovelace"}, {"Alan", "Turing"}}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %v, got %v", expected, keys)
+	}
+}
+
+// Random operations compared with a map, the structure is checked after every operation.
+func TestTreeMap_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+	m := NewTreeMap[int, int]()
+	reference := make(map[int]int)
+	for i := 0; i < 3000; i++ {
+		key := r.Intn(200)
+		switch r.Intn(3) {
+		case 0:
+			_, exists := reference[key]
+			if m.Delete(key) != exists {
+				t.Fatalf("Delete(%d) != %v", key, exists)
+			}
+			delete(reference, key)
+		default:
+			m.Put(key, i)
+			reference[key] = i
+		}
+		checkTreeMap(t, m)
+
+		lo, hi := r.Intn(200), r.Intn(200)
+		expected := []int{}
+		for k := range reference {
+			if lo <= k && k <= hi {
+				expected = append(expected, k)
+			}
+		}
+		sort.Ints(expected)
+		keys, values := collect(m.Range(lo, hi))
+		if !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("Range(%d, %d) = %v, want %v", lo, hi, keys, expected)
+		}
+		for j, k := range keys {
+			if values[j] != reference[k] {
+				t.Fatalf("Range(%d, %d): key %d has value %d, want %d", lo, hi, k, values[j], reference[k])
+			}
+		}
+
+		floor, _, ok := m.Floor(key)
+		expectedFloor, expectedOk := -1, false
+		for k := range reference {
+			if k <= key && k > expectedFloor {
+				expectedFloor, expectedOk = k, true
+			}
+		}
+		if ok != expectedOk || ok && floor != expectedFloor {
+			t.Fatalf("Floor(%d) = %d, %v, want %d, %v", key, floor, ok, expectedFloor, expectedOk)
+		}
+	}
+}
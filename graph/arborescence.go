@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Edge of the (possibly contracted) graph used by the Chu-Liu/Edmonds algorithm.
+// `id` is the index of the edge it was made from in the graph one level up.
+type arborescenceEdge struct {
+	from, to, weight, id int
+}
+
+// Find a minimum spanning arborescence of a directed graph with the Chu-Liu/Edmonds algorithm:
+// a set of edges with the smallest total weight such that there's exactly one path from `root`
+// to every other node. It's the directed version of a minimum spanning tree, see KruskalMST.
+// Return the edges, sorted by their target node, and the total weight, or an error if some node
+// can't be reached from the root. Weights can be negative.
+func (g *Graph) MinimumArborescence(root int) ([]Edge, int, error) {
+	if !g.Directed {
+		panic("MinimumArborescence: cannot be applied to undirected graphs, use KruskalMST.")
+	}
+	if root < 1 || root > g.Nodes {
+		panic(fmt.Sprintf("MinimumArborescence: root should be in range [1, %v], got %v", g.Nodes, root))
+	}
+	reachable := g.BFS(root)
+	if len(reachable) != g.Nodes {
+		return nil, 0, fmt.Errorf("MinimumArborescence: only %v of %v nodes are reachable from %v", len(reachable), g.Nodes, root)
+	}
+
+	edges := []arborescenceEdge{}
+	original := []Edge{}
+	for from := 1; from <= g.Nodes; from++ {
+		for _, edge := range g.AdjacencyList[from] {
+			edges = append(edges, arborescenceEdge{from: from - 1, to: edge.To - 1, weight: edge.Weight, id: len(original)})
+			original = append(original, edge)
+		}
+	}
+
+	arborescence := []Edge{}
+	weight := 0
+	for _, i := range chuLiuEdmonds(g.Nodes, root-1, edges) {
+		arborescence = append(arborescence, original[i])
+		weight += original[i].Weight
+	}
+	sort.Slice(arborescence, func(i, j int) bool {
+		return arborescence[i].To < arborescence[j].To
+	})
+	return arborescence, weight, nil
+}
+
+// Get the indices of the edges of a minimum arborescence, for nodes 0..n-1 that are all reachable
+// from the root. Every node except the root picks its cheapest incoming edge. If these edges have
+// no cycle, they are the answer. Otherwise, each cycle is contracted into a single node and the weight
+// of every edge entering the cycle is reduced by the weight of the cycle edge it would replace.
+// An arborescence of the contracted graph is found recursively, and then expanded: each cycle keeps
+// all its edges except the one into the node where the chosen entering edge arrives.
+func chuLiuEdmonds(n int, root int, edges []arborescenceEdge) []int {
+	// Cheapest incoming edge of every node, the first one in case of ties.
+	in := make([]int, n)
+	for v := range in {
+		in[v] = -1
+	}
+	for i, edge := range edges {
+		if edge.to != root && edge.from != edge.to && (in[edge.to] == -1 || edge.weight < edges[in[edge.to]].weight) {
+			in[edge.to] = i
+		}
+	}
+
+	// Follow the chosen edges backwards from every node, to find the cycles.
+	// component[v] is the node of the contracted graph that v belongs to.
+	component := make([]int, n)
+	visitedBy := make([]int, n)
+	for v := range n {
+		component[v] = -1
+		visitedBy[v] = -1
+	}
+	components := 0
+	hasCycle := false
+	for start := range n {
+		v := start
+		for v != root && visitedBy[v] == -1 && component[v] == -1 {
+			visitedBy[v] = start
+			v = edges[in[v]].from
+		}
+		// Came back to a node seen in this walk, so there's a cycle through it.
+		if v != root && visitedBy[v] == start && component[v] == -1 {
+			hasCycle = true
+			for u := v; component[u] == -1; u = edges[in[u]].from {
+				component[u] = components
+			}
+			components++
+		}
+	}
+	if !hasCycle {
+		chosen := []int{}
+		for v := range n {
+			if v != root {
+				chosen = append(chosen, edges[in[v]].id)
+			}
+		}
+		return chosen
+	}
+	for v := range n {
+		if component[v] == -1 {
+			component[v] = components
+			components++
+		}
+	}
+
+	// Contract the cycles. An edge entering a cycle at v costs its weight minus the weight of in[v],
+	// as it replaces that edge. Edges inside a component are dropped.
+	inCycle := func(v int) bool {
+		return v != root && component[v] == component[edges[in[v]].from]
+	}
+	contracted := []arborescenceEdge{}
+	for i, edge := range edges {
+		from, to := component[edge.from], component[edge.to]
+		if from == to {
+			continue
+		}
+		weight := edge.weight
+		if inCycle(edge.to) {
+			weight -= edges[in[edge.to]].weight
+		}
+		contracted = append(contracted, arborescenceEdge{from: from, to: to, weight: weight, id: i})
+	}
+
+	// Expand: an edge entering a cycle at v replaces in[v], the other cycle edges stay.
+	replaced := make(map[int]bool)
+	chosen := []int{}
+	for _, i := range chuLiuEdmonds(components, component[root], contracted) {
+		edge := edges[i]
+		chosen = append(chosen, edge.id)
+		if inCycle(edge.to) {
+			replaced[edge.to] = true
+		}
+	}
+	for v := range n {
+		if inCycle(v) && !replaced[v] {
+			chosen = append(chosen, edges[in[v]].id)
+		}
+	}
+	return chosen
+}
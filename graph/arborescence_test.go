@@ -0,0 +1,157 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMinimumArborescence(t *testing.T) {
+	// The cheapest incoming edges of 2 and 3 form a cycle 2 -> 3 -> 2, which must be broken
+	// by entering it from the root. Entering at 3 (1 -> 3, cost 5 instead of 1) is cheaper
+	// than entering at 2 (1 -> 2, cost 10 instead of 1).
+	g := NewEmptyGraph(true)
+	g.AddNodes(4)
+	g.ConnectNodes(1, 2, 10)
+	g.ConnectNodes(1, 3, 5)
+	g.ConnectNodes(2, 3, 1)
+	g.ConnectNodes(3, 2, 1)
+	g.ConnectNodes(3, 4, 2)
+	g.ConnectNodes(2, 4, 3)
+
+	edges, weight, err := g.MinimumArborescence(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []Edge{{From: 3, To: 2, Weight: 1}, {From: 1, To: 3, Weight: 5}, {From: 3, To: 4, Weight: 2}}
+	if weight != 8 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("Expected %v with weight 8, got %v with weight %d", expected, edges, weight)
+	}
+
+	edges, weight, err = g.MinimumArborescence(2)
+	if err == nil {
+		t.Errorf("Expected an error, node 1 is unreachable from 2, got %v with weight %d", edges, weight)
+	}
+
+	single := NewEmptyGraph(true)
+	single.AddNodes(1)
+	edges, weight, err = single.MinimumArborescence(1)
+	if err != nil || len(edges) != 0 || weight != 0 {
+		t.Errorf("Expected an empty arborescence, got %v, %d, %v", edges, weight, err)
+	}
+}
+
+func TestMinimumArborescencePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected MinimumArborescence to panic on undirected graph")
+		}
+	}()
+	g := NewEmptyGraph(false)
+	g.AddNodes(2)
+	g.ConnectNodes(1, 2, 1)
+	g.MinimumArborescence(1)
+}
+
+// Get the weight of the minimum arborescence by trying every choice of one incoming edge per node,
+// or math.MaxInt if there's none. The choice is an arborescence if every node leads back to the root.
+func bruteForceArborescenceWeight(g *Graph, root int) int {
+	incoming := make(map[int][]Edge)
+	for _, edge := range edgeList(g) {
+		if edge.To != root {
+			incoming[edge.To] = append(incoming[edge.To], edge)
+		}
+	}
+
+	best := math.MaxInt
+	parent := make(map[int]Edge)
+	var choose func(node int)
+	choose = func(node int) {
+		if node == root {
+			choose(node + 1)
+			return
+		}
+		if node > g.Nodes {
+			weight := 0
+			for v := 1; v <= g.Nodes; v++ {
+				if v == root {
+					continue
+				}
+				weight += parent[v].Weight
+				// A path without a cycle reaches the root in less than n steps.
+				u, steps := v, 0
+				for u != root && steps < g.Nodes {
+					u, steps = parent[u].From, steps+1
+				}
+				if u != root {
+					return
+				}
+			}
+			best = min(best, weight)
+			return
+		}
+		for _, edge := range incoming[node] {
+			parent[node] = edge
+			choose(node + 1)
+		}
+	}
+	choose(1)
+	return best
+}
+
+func TestMinimumArborescenceRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	for i := 0; i < 300; i++ {
+		nodes := r.Intn(6) + 1
+		g := NewEmptyGraph(true)
+		g.AddNodes(nodes)
+		for from := 1; from <= nodes; from++ {
+			for to := 1; to <= nodes; to++ {
+				if from != to && r.Float64() < 0.5 {
+					weight := r.Intn(15) - 5
+					if weight == 0 {
+						weight = 1
+					}
+					g.ConnectNodes(from, to, weight)
+				}
+			}
+		}
+		root := r.Intn(nodes) + 1
+
+		expected := bruteForceArborescenceWeight(&g, root)
+		edges, weight, err := g.MinimumArborescence(root)
+		if expected == math.MaxInt {
+			if err == nil {
+				t.Fatalf("Expected an error for root %d, got %v", root, edges)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected no error for root %d, got %v", root, err)
+		}
+		if weight != expected {
+			t.Fatalf("Expected weight %d, got %d with edges %v", expected, weight, edges)
+		}
+
+		// The edges must be from the graph, one into every node except the root.
+		if len(edges) != nodes-1 {
+			t.Fatalf("Expected %d edges, got %v", nodes-1, edges)
+		}
+		sum := 0
+		seen := make(map[int]bool)
+		for _, edge := range edges {
+			if w, exists := g.edgeWeight(edge.From, edge.To); !exists || w != edge.Weight {
+				t.Fatalf("Edge %v is not in the graph", edge)
+			}
+			if edge.To == root || seen[edge.To] {
+				t.Fatalf("Node %d has more than one parent or is the root, edges %v", edge.To, edges)
+			}
+			seen[edge.To] = true
+			sum += edge.Weight
+		}
+		if sum != weight {
+			t.Fatalf("Weight %d doesn't match the sum of the edges %d", weight, sum)
+		}
+	}
+}
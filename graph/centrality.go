@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Get the out-degree of every node, ie. the length of its adjacency list.
+// For undirected graphs it's simply the degree of a node.
+func (g *Graph) outDegree() map[int]int {
+	outDegree := make(map[int]int)
+	for node := 1; node <= g.Nodes; node++ {
+		outDegree[node] = len(g.AdjacencyList[node])
+	}
+	return outDegree
+}
+
+// Normalize degrees by the maximum possible degree, n-1. For a graph with a single node return 0.
+func normalizeDegrees(degrees map[int]int, nodes int) map[int]float64 {
+	result := make(map[int]float64)
+	for node, degree := range degrees {
+		if nodes > 1 {
+			result[node] = float64(degree) / float64(nodes-1)
+		} else {
+			result[node] = 0
+		}
+	}
+	return result
+}
+
+// Degree centrality: the number of neighbors of each node, divided by n-1.
+// For directed graphs both incoming and outgoing edges count, so the values can exceed 1.
+func (g *Graph) DegreeCentrality() map[int]float64 {
+	degrees := g.outDegree()
+	if g.Directed {
+		for node, degree := range g.inDegree() {
+			degrees[node] += degree
+		}
+	}
+	return normalizeDegrees(degrees, g.Nodes)
+}
+
+// In-degree centrality: the number of incoming edges of each node, divided by n-1.
+// Only for directed graphs.
+func (g *Graph) InDegreeCentrality() map[int]float64 {
+	if !g.Directed {
+		panic("InDegreeCentrality: cannot be applied to undirected graphs.")
+	}
+	return normalizeDegrees(g.inDegree(), g.Nodes)
+}
+
+// Out-degree centrality: the number of outgoing edges of each node, divided by n-1.
+// Only for directed graphs.
+func (g *Graph) OutDegreeCentrality() map[int]float64 {
+	if !g.Directed {
+		panic("OutDegreeCentrality: cannot be applied to undirected graphs.")
+	}
+	return normalizeDegrees(g.outDegree(), g.Nodes)
+}
+
+// Closeness centrality, based on the weighted shortest path distances from each node.
+// For a node that reaches r other nodes with the sum of distances d, closeness is (r/d) * (r/(n-1)).
+// The first factor is the inverse of the average distance, the second one scales it down for
+// nodes that can reach only a part of the graph (Wasserman and Faust). Nodes which can't reach
+// any other node have closeness 0. In directed graphs outgoing distances are used.
+func (g *Graph) ClosenessCentrality() map[int]float64 {
+	closeness := make(map[int]float64)
+	for node := 1; node <= g.Nodes; node++ {
+		dist, _ := g.Dijkstra(node)
+		reachable, total := 0, 0
+		for other, d := range dist {
+			if other != node && d != math.MaxInt {
+				reachable++
+				total += d
+			}
+		}
+		if reachable == 0 || total == 0 {
+			closeness[node] = 0
+			continue
+		}
+		r := float64(reachable)
+		closeness[node] = (r / float64(total)) * (r / float64(g.Nodes-1))
+	}
+	return closeness
+}
+
+// Betweenness centrality using Brandes' algorithm. Betweenness of a node v is the sum, over all pairs
+// of other nodes (s, t), of the fraction of shortest s-t paths that go through v. Weights are taken
+// into account, so they should be positive.
+//
+// For each source s, a Dijkstra-like pass counts the number of shortest paths sigma[v] from s to
+// every v and keeps all predecessors on shortest paths. Then the nodes are processed in order of
+// decreasing distance and each one passes its dependency to its predecessors:
+// delta[p] += sigma[p]/sigma[v] * (1 + delta[v]).
+//
+// For undirected graphs every pair is counted once. If `normalized` is true, the values are divided
+// by the number of pairs of other nodes, so they are in [0, 1].
+func (g *Graph) BetweennessCentrality(normalized bool) map[int]float64 {
+	betweenness := make(map[int]float64)
+	for node := 1; node <= g.Nodes; node++ {
+		betweenness[node] = 0
+	}
+
+	for source := 1; source <= g.Nodes; source++ {
+		order, preds, sigma := g.shortestPathCounts(source)
+
+		delta := make(map[int]float64)
+		for i := len(order) - 1; i >= 0; i-- { // From the furthest node back to the source.
+			v := order[i]
+			for _, p := range preds[v] {
+				delta[p] += sigma[p] / sigma[v] * (1 + delta[v])
+			}
+			if v != source {
+				betweenness[v] += delta[v]
+			}
+		}
+	}
+
+	scale := 1.0
+	if normalized && g.Nodes > 2 {
+		// Divide by the number of ordered pairs of other nodes. For undirected graphs there are half as
+		// many unordered pairs, but each of them was counted twice, so the scale is the same.
+		scale = 1 / float64((g.Nodes-1)*(g.Nodes-2))
+	} else if !g.Directed {
+		scale = 0.5 // Each pair was counted from both ends.
+	}
+	for node := range betweenness {
+		betweenness[node] *= scale
+	}
+	return betweenness
+}
+
+// Helper for BetweennessCentrality. Run Dijkstra's algorithm from `source`, keeping all predecessors
+// on shortest paths and counting the shortest paths. Return the reachable nodes in order of
+// non-decreasing distance, the predecessors, and the number of shortest paths to each node.
+func (g *Graph) shortestPathCounts(source int) ([]int, map[int][]int, map[int]float64) {
+	priorities := make([]int, g.Nodes)
+	values := make([]int, g.Nodes)
+	dist := make(map[int]int)
+	for i := range g.Nodes {
+		values[i] = i + 1
+		priorities[i] = math.MaxInt
+		dist[i+1] = math.MaxInt
+	}
+	priorities[source-1] = 0
+	dist[source] = 0
+	prioQueue := NewHeap(priorities, values)
+
+	order := []int{}
+	preds := make(map[int][]int)
+	sigma := map[int]float64{source: 1} // Float, as the number of paths grows exponentially.
+
+	for prioQueue.Len() > 0 {
+		_, v, _ := prioQueue.PopMin()
+		if dist[v] == math.MaxInt {
+			break // All the remaining nodes are unreachable.
+		}
+		order = append(order, v)
+		for _, edge := range g.AdjacencyList[v] {
+			alt := dist[v] + edge.Weight
+			if alt < dist[edge.To] {
+				dist[edge.To] = alt
+				sigma[edge.To] = sigma[v]
+				preds[edge.To] = []int{v}
+				prioQueue.DecreasePrio(edge.To, alt)
+			} else if alt == dist[edge.To] {
+				sigma[edge.To] += sigma[v]
+				preds[edge.To] = append(preds[edge.To], v)
+			}
+		}
+	}
+	return order, preds, sigma
+}
+
+// The maximum number of iterations of PageRank, in case the tolerance is too small to be reached.
+const pageRankMaxIterations = 1000
+
+// PageRank computed with power iteration. In each step a node gives its rank, split evenly, to the
+// nodes it links to. With probability 1-damping the random surfer jumps to a random node instead,
+// and nodes without outgoing edges spread their rank over all nodes. Weights are ignored.
+// Iterate until the sum of absolute changes is below `tolerance`. The ranks sum up to 1.
+func (g *Graph) PageRank(damping float64, tolerance float64) map[int]float64 {
+	if damping < 0 || damping >= 1 {
+		panic(fmt.Sprintf("PageRank: damping should be in range [0, 1), got %v", damping))
+	}
+	if tolerance <= 0 {
+		panic(fmt.Sprintf("PageRank: tolerance should be positive, got %v", tolerance))
+	}
+
+	ranks := make(map[int]float64)
+	if g.Nodes == 0 {
+		return ranks
+	}
+	n := float64(g.Nodes)
+	for node := 1; node <= g.Nodes; node++ {
+		ranks[node] = 1 / n
+	}
+
+	for range pageRankMaxIterations {
+		// Rank of dangling nodes (without outgoing edges) is shared by everyone.
+		dangling := 0.0
+		for node := 1; node <= g.Nodes; node++ {
+			if len(g.AdjacencyList[node]) == 0 {
+				dangling += ranks[node]
+			}
+		}
+
+		next := make(map[int]float64)
+		for node := 1; node <= g.Nodes; node++ {
+			next[node] = (1-damping)/n + damping*dangling/n
+		}
+		for node := 1; node <= g.Nodes; node++ {
+			edges := g.AdjacencyList[node]
+			for _, edge := range edges {
+				next[edge.To] += damping * ranks[node] / float64(len(edges))
+			}
+		}
+
+		change := 0.0
+		for node := range ranks {
+			change += math.Abs(next[node] - ranks[node])
+		}
+		ranks = next
+		if change < tolerance {
+			break
+		}
+	}
+	return ranks
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Check that two float maps are equal up to a small tolerance.
+func floatMapsEqual(a, b map[int]float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, exists := b[k]; !exists || math.Abs(v-w) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDegreeCentrality(t *testing.T) {
+	// Star with center 1 and three leaves.
+	star := NewEmptyGraph(false)
+	star.AddNodes(4)
+	star.ConnectNodes(1, 2, 1)
+	star.ConnectNodes(1, 3, 1)
+	star.ConnectNodes(1, 4, 1)
+
+	expected := map[int]float64{1: 1, 2: 1.0 / 3, 3: 1.0 / 3, 4: 1.0 / 3}
+	if got := star.DegreeCentrality(); !floatMapsEqual(got, expected, 1e-9) {
+		t.Errorf("DegreeCentrality = %v, want %v", got, expected)
+	}
+
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(1, 3, 1)
+	g.ConnectNodes(2, 3, 1)
+
+	expectedIn := map[int]float64{1: 0, 2: 0.5, 3: 1}
+	expectedOut := map[int]float64{1: 1, 2: 0.5, 3: 0}
+	expectedAll := map[int]float64{1: 1, 2: 1, 3: 1}
+	if got := g.InDegreeCentrality(); !floatMapsEqual(got, expectedIn, 1e-9) {
+		t.Errorf("InDegreeCentrality = %v, want %v", got, expectedIn)
+	}
+	if got := g.OutDegreeCentrality(); !floatMapsEqual(got, expectedOut, 1e-9) {
+		t.Errorf("OutDegreeCentrality = %v, want %v", got, expectedOut)
+	}
+	if got := g.DegreeCentrality(); !floatMapsEqual(got, expectedAll, 1e-9) {
+		t.Errorf("DegreeCentrality = %v, want %v", got, expectedAll)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected InDegreeCentrality to panic on undirected graph")
+		}
+	}()
+	star.InDegreeCentrality()
+}
+
+func TestClosenessCentrality(t *testing.T) {
+	// Path 1 - 2 - 3 with weights 1 and 2, and an isolated node 4.
+	g := NewEmptyGraph(false)
+	g.AddNodes(4)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 3, 2)
+
+	// Node 1 reaches 2 nodes with total distance 1+3=4: (2/4) * (2/3).
+	expected := map[int]float64{1: 1.0 / 3, 2: 4.0 / 9, 3: 4.0 / 15, 4: 0}
+	if got := g.ClosenessCentrality(); !floatMapsEqual(got, expected, 1e-9) {
+		t.Errorf("ClosenessCentrality = %v, want %v", got, expected)
+	}
+}
+
+// Brute force betweenness: enumerate all simple paths between every pair of nodes, keep the
+// shortest ones and count how many of them go through each node.
+func bruteForceBetweenness(g *Graph) map[int]float64 {
+	dist := floydWarshall(g)
+	result := make(map[int]float64)
+	for node := 1; node <= g.Nodes; node++ {
+		result[node] = 0
+	}
+
+	for s := 1; s <= g.Nodes; s++ {
+		for target := 1; target <= g.Nodes; target++ {
+			if s == target || dist[s-1][target-1] == math.MaxInt || (!g.Directed && s > target) {
+				continue
+			}
+			total := 0
+			through := make(map[int]int)
+			path := []int{s}
+			onPath := map[int]bool{s: true}
+			var walk func(v int, length int)
+			walk = func(v int, length int) {
+				if v == target {
+					if length == dist[s-1][target-1] {
+						total++
+						for _, u := range path[1 : len(path)-1] {
+							through[u]++
+						}
+					}
+					return
+				}
+				for _, edge := range g.AdjacencyList[v] {
+					if !onPath[edge.To] {
+						onPath[edge.To] = true
+						path = append(path, edge.To)
+						walk(edge.To, length+edge.Weight)
+						path = path[:len(path)-1]
+						onPath[edge.To] = false
+					}
+				}
+			}
+			walk(s, 0)
+			for u, count := range through {
+				result[u] += float64(count) / float64(total)
+			}
+		}
+	}
+	return result
+}
+
+func TestBetweennessCentrality(t *testing.T) {
+	// In a star, all paths between leaves go through the center: 3 pairs of leaves.
+	star := NewEmptyGraph(false)
+	star.AddNodes(4)
+	star.ConnectNodes(1, 2, 1)
+	star.ConnectNodes(1, 3, 1)
+	star.ConnectNodes(1, 4, 1)
+
+	expected := map[int]float64{1: 3, 2: 0, 3: 0, 4: 0}
+	if got := star.BetweennessCentrality(false); !floatMapsEqual(got, expected, 1e-9) {
+		t.Errorf("BetweennessCentrality = %v, want %v", got, expected)
+	}
+	expectedNormalized := map[int]float64{1: 1, 2: 0, 3: 0, 4: 0}
+	if got := star.BetweennessCentrality(true); !floatMapsEqual(got, expectedNormalized, 1e-9) {
+		t.Errorf("BetweennessCentrality (normalized) = %v, want %v", got, expectedNormalized)
+	}
+
+	// Compare with brute force on random graphs.
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(7)+1, i%2 == 0, r.Float64()*0.6, 3)
+		expected := bruteForceBetweenness(&g)
+		if got := g.BetweennessCentrality(false); !floatMapsEqual(got, expected, 1e-9) {
+			t.Fatalf("BetweennessCentrality = %v, want %v for graph %v", got, expected, g.AdjacencyList)
+		}
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	// On a directed cycle all nodes are equal.
+	cycle := NewEmptyGraph(true)
+	cycle.AddNodes(4)
+	cycle.ConnectNodes(1, 2, 1)
+	cycle.ConnectNodes(2, 3, 1)
+	cycle.ConnectNodes(3, 4, 1)
+	cycle.ConnectNodes(4, 1, 1)
+
+	expected := map[int]float64{1: 0.25, 2: 0.25, 3: 0.25, 4: 0.25}
+	if got := cycle.PageRank(0.85, 1e-10); !floatMapsEqual(got, expected, 1e-9) {
+		t.Errorf("PageRank = %v, want %v", got, expected)
+	}
+
+	// Node 3 gets links from everyone, node 4 from no one. Node 5 is dangling.
+	g := NewEmptyGraph(true)
+	g.AddNodes(5)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(1, 3, 1)
+	g.ConnectNodes(2, 3, 1)
+	g.ConnectNodes(3, 1, 1)
+	g.ConnectNodes(4, 3, 1)
+	g.ConnectNodes(3, 5, 1)
+
+	ranks := g.PageRank(0.85, 1e-12)
+	sum := 0.0
+	for _, rank := range ranks {
+		sum += rank
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("PageRank: ranks should sum up to 1, got %v", sum)
+	}
+	if !(ranks[3] > ranks[1] && ranks[1] > ranks[2] && ranks[2] > ranks[4] && ranks[5] > ranks[4]) {
+		t.Errorf("PageRank: unexpected ranking %v", ranks)
+	}
+	// Node 4 only gets the teleport rank and its share of the dangling node 5.
+	if expected4 := 0.15/5 + 0.85*ranks[5]/5; math.Abs(ranks[4]-expected4) > 1e-9 {
+		t.Errorf("PageRank: expected rank %v for node 4, got %v", expected4, ranks[4])
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected PageRank to panic on invalid damping")
+		}
+	}()
+	g.PageRank(1, 1e-6)
+}
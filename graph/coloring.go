@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Order in which GreedyColoring visits the nodes.
+type ColoringStrategy int
+
+const (
+	NaturalOrder ColoringStrategy = iota // Nodes 1, 2, ..., n.
+	LargestFirst                         // Nodes with higher degree first (Welsh-Powell).
+	DSatur                               // Next is the node with the most distinct colors among its neighbors.
+)
+
+// Get the set of neighbors of every node, to check adjacency in O(1).
+func (g *Graph) neighborSets() map[int]map[int]bool {
+	neighbors := make(map[int]map[int]bool)
+	for node := 1; node <= g.Nodes; node++ {
+		neighbors[node] = make(map[int]bool)
+		for _, edge := range g.AdjacencyList[node] {
+			neighbors[node][edge.To] = true
+		}
+	}
+	return neighbors
+}
+
+// Get the smallest color (starting from 1) not used by any neighbor of `node`.
+func (g *Graph) smallestFreeColor(node int, colors map[int]int) int {
+	used := make(map[int]bool)
+	for _, edge := range g.AdjacencyList[node] {
+		used[colors[edge.To]] = true
+	}
+	color := 1
+	for used[color] {
+		color++
+	}
+	return color
+}
+
+// Color the nodes of an undirected graph so that neighbors have different colors. Nodes are visited
+// in the order given by `strategy` and each gets the smallest color not used by its neighbors.
+// Return a map from nodes to colors 1..k. The number of colors is not necessarily minimal.
+func (g *Graph) GreedyColoring(strategy ColoringStrategy) map[int]int {
+	if g.Directed {
+		panic("GreedyColoring: cannot be applied to directed graphs.")
+	}
+
+	colors := make(map[int]int) // 0 means not colored yet.
+	switch strategy {
+	case NaturalOrder, LargestFirst:
+		order := make([]int, g.Nodes)
+		for i := range order {
+			order[i] = i + 1
+		}
+		if strategy == LargestFirst {
+			sort.SliceStable(order, func(i, j int) bool {
+				return len(g.AdjacencyList[order[i]]) > len(g.AdjacencyList[order[j]])
+			})
+		}
+		for _, node := range order {
+			colors[node] = g.smallestFreeColor(node, colors)
+		}
+	case DSatur:
+		// Saturation of a node is the number of distinct colors among its neighbors.
+		saturation := make(map[int]map[int]bool)
+		for node := 1; node <= g.Nodes; node++ {
+			saturation[node] = make(map[int]bool)
+		}
+		for range g.Nodes {
+			// Pick the uncolored node with the highest saturation, ties broken by degree, then by number.
+			best := 0
+			for node := 1; node <= g.Nodes; node++ {
+				if colors[node] != 0 {
+					continue
+				}
+				if best == 0 || len(saturation[node]) > len(saturation[best]) ||
+					(len(saturation[node]) == len(saturation[best]) && len(g.AdjacencyList[node]) > len(g.AdjacencyList[best])) {
+					best = node
+				}
+			}
+			colors[best] = g.smallestFreeColor(best, colors)
+			for _, edge := range g.AdjacencyList[best] {
+				saturation[edge.To][colors[best]] = true
+			}
+		}
+	default:
+		panic(fmt.Sprintf("GreedyColoring: unknown strategy %v", strategy))
+	}
+	return colors
+}
+
+// Get the number of colors used by a coloring.
+func numColors(colors map[int]int) int {
+	k := 0
+	for _, color := range colors {
+		k = max(k, color)
+	}
+	return k
+}
+
+// Color the nodes of an undirected graph with the minimum number of colors (the chromatic number),
+// using backtracking. For k = 1, 2, ... try to color nodes one by one (highest degree first) with
+// colors 1..k, undoing the choice when a node can't be colored. The search is exponential,
+// so it's only meant for small graphs. Return a map from nodes to colors and the number of colors.
+func (g *Graph) ExactColoring() (map[int]int, int) {
+	if g.Directed {
+		panic("ExactColoring: cannot be applied to directed graphs.")
+	}
+	if g.Nodes == 0 {
+		return map[int]int{}, 0
+	}
+
+	// DSatur gives an upper bound, a maximum clique a lower bound, as all its nodes need different colors.
+	best := g.GreedyColoring(DSatur)
+	upper := numColors(best)
+	lower := len(g.MaximumClique())
+
+	order := make([]int, g.Nodes)
+	for i := range order {
+		order[i] = i + 1
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(g.AdjacencyList[order[i]]) > len(g.AdjacencyList[order[j]])
+	})
+
+	for k := lower; k < upper; k++ {
+		colors := make(map[int]int)
+		var color func(i int) bool
+		color = func(i int) bool {
+			if i == len(order) {
+				return true
+			}
+			node := order[i]
+			used := make(map[int]bool)
+			for _, edge := range g.AdjacencyList[node] {
+				used[colors[edge.To]] = true
+			}
+			for c := 1; c <= k; c++ {
+				if used[c] {
+					continue
+				}
+				colors[node] = c
+				if color(i + 1) {
+					return true
+				}
+			}
+			delete(colors, node)
+			return false
+		}
+		if color(0) {
+			return colors, k
+		}
+	}
+	return best, upper
+}
+
+// Find a maximal independent set of an undirected graph: a set of pairwise non-adjacent nodes, to which
+// no other node can be added. Greedily take the node with the fewest remaining neighbors and remove its
+// neighbors. This is not necessarily the largest independent set, see MaximumIndependentSet.
+// Return the nodes in increasing order.
+func (g *Graph) MaximalIndependentSet() []int {
+	if g.Directed {
+		panic("MaximalIndependentSet: cannot be applied to directed graphs.")
+	}
+
+	removed := make(map[int]bool)
+	set := []int{}
+	for {
+		best, bestDegree := 0, 0
+		for node := 1; node <= g.Nodes; node++ {
+			if removed[node] {
+				continue
+			}
+			degree := 0
+			for _, edge := range g.AdjacencyList[node] {
+				if !removed[edge.To] {
+					degree++
+				}
+			}
+			if best == 0 || degree < bestDegree {
+				best, bestDegree = node, degree
+			}
+		}
+		if best == 0 {
+			break
+		}
+		set = append(set, best)
+		removed[best] = true
+		for _, edge := range g.AdjacencyList[best] {
+			removed[edge.To] = true
+		}
+	}
+	sort.Ints(set)
+	return set
+}
+
+// Find all maximal cliques of an undirected graph with the Bron-Kerbosch algorithm with pivoting.
+// A clique is a set of pairwise adjacent nodes, it's maximal if no other node can be added to it.
+// The recursion keeps R (the current clique), P (candidates that can extend it) and X (nodes that
+// were already tried, to avoid reporting the same clique twice). R is maximal when P and X are empty.
+// Neighbors of the pivot don't need to be tried directly, any maximal clique contains the pivot or
+// one of its non-neighbors. Return each clique with nodes in increasing order.
+func (g *Graph) MaximalCliques() [][]int {
+	if g.Directed {
+		panic("MaximalCliques: cannot be applied to directed graphs.")
+	}
+
+	neighbors := g.neighborSets()
+	cliques := [][]int{}
+	if g.Nodes == 0 {
+		return cliques
+	}
+
+	var bronKerbosch func(r []int, p map[int]bool, x map[int]bool)
+	bronKerbosch = func(r []int, p map[int]bool, x map[int]bool) {
+		if len(p) == 0 && len(x) == 0 {
+			clique := append([]int{}, r...)
+			sort.Ints(clique)
+			cliques = append(cliques, clique)
+			return
+		}
+
+		// Choose the pivot with the most neighbors in P, to skip as many candidates as possible.
+		pivot, pivotCount := 0, -1
+		for _, set := range []map[int]bool{p, x} {
+			for u := range set {
+				count := 0
+				for v := range p {
+					if neighbors[u][v] {
+						count++
+					}
+				}
+				if count > pivotCount || (count == pivotCount && u < pivot) {
+					pivot, pivotCount = u, count
+				}
+			}
+		}
+
+		// Iterate over sorted candidates, so that the output doesn't depend on map ordering.
+		candidates := []int{}
+		for v := range p {
+			if !neighbors[pivot][v] {
+				candidates = append(candidates, v)
+			}
+		}
+		sort.Ints(candidates)
+
+		for _, v := range candidates {
+			newP := make(map[int]bool)
+			newX := make(map[int]bool)
+			for u := range p {
+				if neighbors[v][u] {
+					newP[u] = true
+				}
+			}
+			for u := range x {
+				if neighbors[v][u] {
+					newX[u] = true
+				}
+			}
+			bronKerbosch(append(r, v), newP, newX)
+			delete(p, v)
+			x[v] = true
+		}
+	}
+
+	p := make(map[int]bool)
+	for node := 1; node <= g.Nodes; node++ {
+		p[node] = true
+	}
+	bronKerbosch([]int{}, p, make(map[int]bool))
+	return cliques
+}
+
+// Find a largest clique of an undirected graph, the biggest of all maximal cliques.
+func (g *Graph) MaximumClique() []int {
+	best := []int{}
+	for _, clique := range g.MaximalCliques() {
+		if len(clique) > len(best) {
+			best = clique
+		}
+	}
+	return best
+}
+
+// Find a largest independent set of an undirected graph. An independent set is a clique in the
+// complement of the graph.
+func (g *Graph) MaximumIndependentSet() []int {
+	if g.Directed {
+		panic("MaximumIndependentSet: cannot be applied to directed graphs.")
+	}
+	complement := g.Complement()
+	return complement.MaximumClique()
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Check that every node has a color and neighbors have different colors.
+func isProperColoring(g *Graph, colors map[int]int) bool {
+	for node := 1; node <= g.Nodes; node++ {
+		if colors[node] < 1 {
+			return false
+		}
+		for _, edge := range g.AdjacencyList[node] {
+			if colors[node] == colors[edge.To] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Get the chromatic number by trying every assignment of k colors, for k = 1, 2, ...
+func bruteForceChromaticNumber(g *Graph) int {
+	if g.Nodes == 0 {
+		return 0
+	}
+	for k := 1; ; k++ {
+		colors := make(map[int]int)
+		var try func(node int) bool
+		try = func(node int) bool {
+			if node > g.Nodes {
+				return isProperColoring(g, colors)
+			}
+			for c := 1; c <= k; c++ {
+				colors[node] = c
+				if try(node + 1) {
+					return true
+				}
+			}
+			return false
+		}
+		if try(1) {
+			return k
+		}
+	}
+}
+
+// Check if all pairs of nodes are adjacent (clique) or all are non-adjacent (independent set).
+func allPairs(g *Graph, nodes []int, adjacent bool) bool {
+	for i := range nodes {
+		for j := i + 1; j < len(nodes); j++ {
+			if g.edgeExists(nodes[i], nodes[j]) != adjacent {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Get the size of the largest set of nodes where all pairs are adjacent (or all non-adjacent).
+func bruteForceLargestSet(g *Graph, adjacent bool) int {
+	best := 0
+	for mask := 0; mask < 1<<g.Nodes; mask++ {
+		nodes := []int{}
+		for node := 1; node <= g.Nodes; node++ {
+			if mask&(1<<(node-1)) != 0 {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) > best && allPairs(g, nodes, adjacent) {
+			best = len(nodes)
+		}
+	}
+	return best
+}
+
+func TestGreedyColoring(t *testing.T) {
+	// Crown graph: greedy coloring in natural order needs 4 colors, but 2 are enough.
+	crown := NewEmptyGraph(false)
+	crown.AddNodes(8)
+	for i := 1; i <= 4; i++ {
+		for j := 1; j <= 4; j++ {
+			if i != j && !crown.edgeExists(2*i-1, 2*j) {
+				crown.ConnectNodes(2*i-1, 2*j, 1)
+			}
+		}
+	}
+	if colors := crown.GreedyColoring(NaturalOrder); numColors(colors) != 4 {
+		t.Errorf("GreedyColoring(NaturalOrder) on a crown graph: expected 4 colors, got %v", colors)
+	}
+	if colors := crown.GreedyColoring(DSatur); numColors(colors) != 2 {
+		t.Errorf("GreedyColoring(DSatur) on a crown graph: expected 2 colors, got %v", colors)
+	}
+
+	r := rand.New(rand.NewSource(12))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(10)+1, false, r.Float64(), 1)
+		for _, strategy := range []ColoringStrategy{NaturalOrder, LargestFirst, DSatur} {
+			if colors := g.GreedyColoring(strategy); !isProperColoring(&g, colors) {
+				t.Fatalf("GreedyColoring(%v) = %v is not a proper coloring of %v", strategy, colors, g.AdjacencyList)
+			}
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected GreedyColoring to panic on directed graph")
+		}
+	}()
+	directed := NewEmptyGraph(true)
+	directed.GreedyColoring(DSatur)
+}
+
+func TestExactColoring(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(7)+1, false, r.Float64(), 1)
+		colors, k := g.ExactColoring()
+		if !isProperColoring(&g, colors) || numColors(colors) != k {
+			t.Fatalf("ExactColoring = %v, %d is not a proper coloring of %v", colors, k, g.AdjacencyList)
+		}
+		if expected := bruteForceChromaticNumber(&g); k != expected {
+			t.Fatalf("ExactColoring: got %d colors, want %d for %v", k, expected, g.AdjacencyList)
+		}
+	}
+}
+
+func TestMaximalCliques(t *testing.T) {
+	// Two triangles sharing the edge 2-3, and a pendant node 5.
+	g := NewEmptyGraph(false)
+	g.AddNodes(5)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(1, 3, 1)
+	g.ConnectNodes(2, 3, 1)
+	g.ConnectNodes(2, 4, 1)
+	g.ConnectNodes(3, 4, 1)
+	g.ConnectNodes(4, 5, 1)
+
+	cliques := g.MaximalCliques()
+	expected := map[string]bool{"[1 2 3]": true, "[2 3 4]": true, "[4 5]": true}
+	if len(cliques) != len(expected) {
+		t.Fatalf("MaximalCliques = %v, want %v", cliques, expected)
+	}
+	for _, clique := range cliques {
+		if !expected[fmt.Sprint(clique)] {
+			t.Errorf("MaximalCliques: unexpected clique %v", clique)
+		}
+	}
+
+	r := rand.New(rand.NewSource(14))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(9)+1, false, r.Float64(), 1)
+		for _, clique := range g.MaximalCliques() {
+			if !allPairs(&g, clique, true) {
+				t.Fatalf("MaximalCliques: %v is not a clique of %v", clique, g.AdjacencyList)
+			}
+		}
+		if clique := g.MaximumClique(); len(clique) != bruteForceLargestSet(&g, true) {
+			t.Fatalf("MaximumClique = %v, want size %d for %v", clique, bruteForceLargestSet(&g, true), g.AdjacencyList)
+		}
+	}
+}
+
+func TestIndependentSets(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(9)+1, false, r.Float64(), 1)
+
+		maximal := g.MaximalIndependentSet()
+		if !allPairs(&g, maximal, false) {
+			t.Fatalf("MaximalIndependentSet: %v is not independent in %v", maximal, g.AdjacencyList)
+		}
+		// Maximal: every other node has a neighbor in the set.
+		for node := 1; node <= g.Nodes; node++ {
+			if !allPairs(&g, append([]int{node}, maximal...), false) {
+				continue
+			}
+			inSet := false
+			for _, v := range maximal {
+				inSet = inSet || v == node
+			}
+			if !inSet {
+				t.Fatalf("MaximalIndependentSet: %v can be extended with %d in %v", maximal, node, g.AdjacencyList)
+			}
+		}
+
+		maximum := g.MaximumIndependentSet()
+		if !allPairs(&g, maximum, false) || len(maximum) != bruteForceLargestSet(&g, false) {
+			t.Fatalf("MaximumIndependentSet = %v, want size %d for %v", maximum, bruteForceLargestSet(&g, false), g.AdjacencyList)
+		}
+	}
+
+	// A path 1-2-3-4-5, the largest independent set is {1, 3, 5}.
+	path := NewEmptyGraph(false)
+	path.AddNodes(5)
+	for i := 1; i < 5; i++ {
+		path.ConnectNodes(i, i+1, 1)
+	}
+	if got := path.MaximumIndependentSet(); !reflect.DeepEqual(got, []int{1, 3, 5}) {
+		t.Errorf("MaximumIndependentSet = %v, want [1 3 5]", got)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Result of the critical path method. Nodes are events, edges are activities and their weights
+// are durations. A node can happen only after all the activities leading to it are finished.
+type CriticalPathResult struct {
+	Path     []int       // Nodes on a critical path, ie. the longest path in the graph.
+	Length   int         // Total weight of the critical path, ie. the duration of the whole project.
+	Earliest map[int]int // Earliest time at which each node can happen.
+	Latest   map[int]int // Latest time at which each node can happen without delaying the project.
+	Slack    map[int]int // Latest - Earliest, how much each node can be delayed. Zero on critical paths.
+}
+
+// Critical path method on a weighted DAG. In topological order, the earliest time of a node is
+// the maximum over incoming edges of the earliest time of the source plus the weight, ie. the longest
+// path to it. Then, in reverse order, the latest time of a node is the minimum over outgoing edges of
+// the latest time of the target minus the weight, starting from the project length at the sinks.
+// Return an error if the graph has a cycle.
+func (g *Graph) CriticalPath() (CriticalPathResult, error) {
+	order, err := g.KahnTopoSort()
+	if err != nil {
+		return CriticalPathResult{}, err
+	}
+
+	earliest := make(map[int]int)
+	prev := make(map[int]int) // Predecessor on the longest path to each node, 0 for nodes with no incoming edges.
+	for _, node := range order {
+		earliest[node] = 0
+		prev[node] = 0
+	}
+	for _, node := range order {
+		for _, edge := range g.AdjacencyList[node] {
+			if earliest[node]+edge.Weight > earliest[edge.To] {
+				earliest[edge.To] = earliest[node] + edge.Weight
+				prev[edge.To] = node
+			}
+		}
+	}
+
+	// The project ends with the node that has the latest earliest time.
+	end, length := 0, math.MinInt
+	for _, node := range order {
+		if earliest[node] > length {
+			end, length = node, earliest[node]
+		}
+	}
+	if end == 0 {
+		return CriticalPathResult{Path: []int{}, Earliest: earliest, Latest: map[int]int{}, Slack: map[int]int{}}, nil
+	}
+
+	latest := make(map[int]int)
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		latest[node] = length
+		for _, edge := range g.AdjacencyList[node] {
+			latest[node] = min(latest[node], latest[edge.To]-edge.Weight)
+		}
+	}
+
+	slack := make(map[int]int)
+	for _, node := range order {
+		slack[node] = latest[node] - earliest[node]
+	}
+
+	path := []int{}
+	for node := end; node != 0; node = prev[node] {
+		path = append(path, node)
+	}
+	slices.Reverse(path) // The path was built backwards.
+
+	return CriticalPathResult{Path: path, Length: length, Earliest: earliest, Latest: latest, Slack: slack}, nil
+}
+
+// Find the path with the largest total weight in a DAG. Return its nodes and weight,
+// or an error if the graph has a cycle.
+func (g *Graph) LongestPath() ([]int, int, error) {
+	result, err := g.CriticalPath()
+	if err != nil {
+		return nil, 0, err
+	}
+	return result.Path, result.Length, nil
+}
+
+// Get the reachability sets of a DAG: reach[v] contains every node reachable from v by a path
+// of at least one edge. Nodes are processed in reverse topological order, so the sets of all
+// successors are complete when they're merged.
+func (g *Graph) reachability(order []int) map[int]*Set {
+	reach := make(map[int]*Set)
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		reach[node] = NewSet()
+		for _, edge := range g.AdjacencyList[node] {
+			reach[node].Add(edge.To)
+			for v := range reach[edge.To].elements {
+				reach[node].Add(v)
+			}
+		}
+	}
+	return reach
+}
+
+// Get the transitive closure of a DAG: a graph with an edge u -> v (with weight 1) whenever there's
+// a path from u to v in the original graph. Return an error if the graph has a cycle.
+func (g *Graph) TransitiveClosure() (Graph, error) {
+	order, err := g.KahnTopoSort()
+	if err != nil {
+		return Graph{}, err
+	}
+
+	reach := g.reachability(order)
+	closure := NewEmptyGraph(true)
+	if g.Nodes > 0 {
+		closure.AddNodes(g.Nodes)
+	}
+	for from := 1; from <= g.Nodes; from++ {
+		for to := 1; to <= g.Nodes; to++ { // In order, so that the adjacency lists are sorted.
+			if reach[from].Contains(to) {
+				closure.ConnectNodes(from, to, 1)
+			}
+		}
+	}
+	return closure, nil
+}
+
+// Get the transitive reduction of a DAG: the graph with the fewest edges that has the same
+// reachability. An edge u -> v is redundant if v can be reached from another successor of u.
+// The remaining edges keep their weights. Return an error if the graph has a cycle.
+func (g *Graph) TransitiveReduction() (Graph, error) {
+	order, err := g.KahnTopoSort()
+	if err != nil {
+		return Graph{}, err
+	}
+
+	reach := g.reachability(order)
+	reduction := NewEmptyGraph(true)
+	if g.Nodes > 0 {
+		reduction.AddNodes(g.Nodes)
+	}
+	for from := 1; from <= g.Nodes; from++ {
+		for _, edge := range g.AdjacencyList[from] {
+			redundant := false
+			for _, other := range g.AdjacencyList[from] {
+				if other.To != edge.To && reach[other.To].Contains(edge.To) {
+					redundant = true
+					break
+				}
+			}
+			if !redundant {
+				reduction.ConnectNodes(from, edge.To, edge.Weight)
+			}
+		}
+	}
+	return reduction, nil
+}
+
+// Count the distinct paths from `from` to `to` in a DAG. In topological order, the number of paths
+// to a node is the sum of the numbers of paths to its predecessors. A node has one (empty) path to
+// itself. The count can grow exponentially, it overflows for very large DAGs.
+// Return an error if the graph has a cycle.
+func (g *Graph) CountPaths(from int, to int) (int, error) {
+	if from < 1 || from > g.Nodes || to < 1 || to > g.Nodes {
+		panic(fmt.Sprintf("CountPaths: from and to should be in range [1, %v], got %v and %v", g.Nodes, from, to))
+	}
+	order, err := g.KahnTopoSort()
+	if err != nil {
+		return 0, err
+	}
+
+	paths := map[int]int{from: 1}
+	for _, node := range order {
+		if paths[node] == 0 {
+			continue // Not reachable from `from`, or before it in the order.
+		}
+		for _, edge := range g.AdjacencyList[node] {
+			paths[edge.To] += paths[node]
+		}
+	}
+	return paths[to], nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Get a random DAG, edges only go from smaller to larger nodes.
+func randomDAG(r *rand.Rand, nodes int, density float64, maxWeight int) Graph {
+	g := NewEmptyGraph(true)
+	g.AddNodes(nodes)
+	for from := 1; from <= nodes; from++ {
+		for to := from + 1; to <= nodes; to++ {
+			if r.Float64() < density {
+				g.ConnectNodes(from, to, r.Intn(maxWeight)+1)
+			}
+		}
+	}
+	return g
+}
+
+// Get a small cyclic graph, to check that DAG algorithms reject it.
+func cyclicGraph() Graph {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 3, 1)
+	g.ConnectNodes(3, 1, 1)
+	return g
+}
+
+func TestCriticalPath(t *testing.T) {
+	// A small project: 1 is the start, 6 the end.
+	g := NewEmptyGraph(true)
+	g.AddNodes(6)
+	g.ConnectNodes(1, 2, 3)
+	g.ConnectNodes(1, 3, 2)
+	g.ConnectNodes(2, 4, 4)
+	g.ConnectNodes(3, 4, 1)
+	g.ConnectNodes(3, 5, 5)
+	g.ConnectNodes(4, 6, 2)
+	g.ConnectNodes(5, 6, 1)
+
+	result, err := g.CriticalPath()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Length != 9 || !slicesEqual(result.Path, []int{1, 2, 4, 6}) {
+		t.Errorf("CriticalPath: got path %v of length %d, want [1 2 4 6] of length 9", result.Path, result.Length)
+	}
+	expectedEarliest := map[int]int{1: 0, 2: 3, 3: 2, 4: 7, 5: 7, 6: 9}
+	expectedLatest := map[int]int{1: 0, 2: 3, 3: 3, 4: 7, 5: 8, 6: 9}
+	expectedSlack := map[int]int{1: 0, 2: 0, 3: 1, 4: 0, 5: 1, 6: 0}
+	if !reflect.DeepEqual(result.Earliest, expectedEarliest) {
+		t.Errorf("CriticalPath: earliest = %v, want %v", result.Earliest, expectedEarliest)
+	}
+	if !reflect.DeepEqual(result.Latest, expectedLatest) {
+		t.Errorf("CriticalPath: latest = %v, want %v", result.Latest, expectedLatest)
+	}
+	if !reflect.DeepEqual(result.Slack, expectedSlack) {
+		t.Errorf("CriticalPath: slack = %v, want %v", result.Slack, expectedSlack)
+	}
+
+	path, length, err := g.LongestPath()
+	if err != nil || length != 9 || !slicesEqual(path, []int{1, 2, 4, 6}) {
+		t.Errorf("LongestPath = %v, %d, %v", path, length, err)
+	}
+
+	cyclic := cyclicGraph()
+	if _, err := cyclic.CriticalPath(); err == nil {
+		t.Errorf("Expected an error for a cyclic graph")
+	}
+}
+
+// Check critical paths on random DAGs against brute force enumeration of all paths.
+func TestCriticalPathRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(16))
+	for i := 0; i < 100; i++ {
+		g := randomDAG(r, r.Intn(8)+1, r.Float64(), 10)
+		result, err := g.CriticalPath()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		longest := 0
+		for from := 1; from <= g.Nodes; from++ {
+			for to := 1; to <= g.Nodes; to++ {
+				for _, p := range allSimplePaths(&g, from, to) {
+					longest = max(longest, p.Weight)
+				}
+			}
+		}
+		if result.Length != longest || g.pathWeight(result.Path) != longest {
+			t.Fatalf("CriticalPath: got %v of length %d, want length %d", result.Path, result.Length, longest)
+		}
+		for node := 1; node <= g.Nodes; node++ {
+			if result.Slack[node] < 0 || result.Slack[node] != result.Latest[node]-result.Earliest[node] {
+				t.Fatalf("CriticalPath: invalid slack %d for node %d", result.Slack[node], node)
+			}
+		}
+		for _, node := range result.Path {
+			if result.Slack[node] != 0 {
+				t.Fatalf("CriticalPath: node %d on the critical path has slack %d", node, result.Slack[node])
+			}
+		}
+		// No activity can start before its source event or end after its target event.
+		for _, edge := range edgeList(&g) {
+			if result.Earliest[edge.From]+edge.Weight > result.Earliest[edge.To] || result.Latest[edge.From]+edge.Weight > result.Latest[edge.To] {
+				t.Fatalf("CriticalPath: edge %v violates the schedule", edge)
+			}
+		}
+	}
+}
+
+func TestTransitiveClosureAndReduction(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(4)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 3, 1)
+	g.ConnectNodes(1, 3, 5) // Redundant, 1 -> 2 -> 3.
+	g.ConnectNodes(3, 4, 1)
+	g.ConnectNodes(1, 4, 7) // Redundant.
+
+	closure, err := g.TransitiveClosure()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedClosure := [][2]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}
+	if edges := edgeList(&closure); len(edges) != len(expectedClosure) {
+		t.Errorf("TransitiveClosure: got edges %v, want %v", edges, expectedClosure)
+	}
+	for _, e := range expectedClosure {
+		if !closure.edgeExists(e[0], e[1]) {
+			t.Errorf("TransitiveClosure: missing edge %v", e)
+		}
+	}
+
+	reduction, err := g.TransitiveReduction()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedReduction := []Edge{newEdge(1, 2, 1), newEdge(2, 3, 1), newEdge(3, 4, 1)}
+	if edges := edgeList(&reduction); !reflect.DeepEqual(edges, expectedReduction) {
+		t.Errorf("TransitiveReduction: got edges %v, want %v", edges, expectedReduction)
+	}
+
+	// On random DAGs, the reduction has the same closure and removing any of its edges changes it.
+	r := rand.New(rand.NewSource(17))
+	for i := 0; i < 50; i++ {
+		g := randomDAG(r, r.Intn(8)+1, r.Float64(), 1)
+		closure, _ := g.TransitiveClosure()
+		reduction, _ := g.TransitiveReduction()
+		reductionClosure, _ := reduction.TransitiveClosure()
+		if !reflect.DeepEqual(closure, reductionClosure) {
+			t.Fatalf("TransitiveReduction changed reachability of %v", g.AdjacencyList)
+		}
+		for _, edge := range edgeList(&reduction) {
+			smaller := reduction.withoutEdges(map[int]bool{}, map[[2]int]bool{{edge.From, edge.To}: true})
+			smallerClosure, _ := smaller.TransitiveClosure()
+			if reflect.DeepEqual(closure, smallerClosure) {
+				t.Fatalf("TransitiveReduction: edge %v is redundant in %v", edge, reduction.AdjacencyList)
+			}
+		}
+	}
+
+	cyclic := cyclicGraph()
+	if _, err := cyclic.TransitiveClosure(); err == nil {
+		t.Errorf("Expected an error for a cyclic graph")
+	}
+	if _, err := cyclic.TransitiveReduction(); err == nil {
+		t.Errorf("Expected an error for a cyclic graph")
+	}
+}
+
+func TestCountPaths(t *testing.T) {
+	// A ladder, each step doubles the number of paths.
+	g := NewEmptyGraph(true)
+	g.AddNodes(7)
+	for i := 1; i <= 5; i += 2 {
+		g.ConnectNodes(i, i+1, 1)
+		g.ConnectNodes(i, i+2, 1)
+		g.ConnectNodes(i+1, i+2, 1)
+	}
+	tests := []struct{ from, to, expected int }{
+		{1, 7, 8}, {1, 5, 4}, {3, 7, 4}, {7, 1, 0}, {4, 4, 1}, {2, 6, 2},
+	}
+	for _, tt := range tests {
+		if count, err := g.CountPaths(tt.from, tt.to); err != nil || count != tt.expected {
+			t.Errorf("CountPaths(%d, %d) = %d, %v; want %d", tt.from, tt.to, count, err, tt.expected)
+		}
+	}
+
+	// Compare with enumerating all paths on random DAGs.
+	r := rand.New(rand.NewSource(18))
+	for i := 0; i < 50; i++ {
+		g := randomDAG(r, r.Intn(8)+1, r.Float64(), 1)
+		from, to := r.Intn(g.Nodes)+1, r.Intn(g.Nodes)+1
+		if count, _ := g.CountPaths(from, to); count != len(allSimplePaths(&g, from, to)) {
+			t.Fatalf("CountPaths(%d, %d) = %d, want %d", from, to, count, len(allSimplePaths(&g, from, to)))
+		}
+	}
+
+	cyclic := cyclicGraph()
+	if _, err := cyclic.CountPaths(1, 3); err == nil {
+		t.Errorf("Expected an error for a cyclic graph")
+	}
+}
@@ -8,7 +8,8 @@ import (
 // Dijkstra's algorithm using min heap priority queue.
 // Calculate minimum distance from a source node to every other node.
 // Return a map of shortest distances to each node, and also a map of predecessor nodes
-// on the shortest path from the source.
+// on the shortest path from the source. Nodes are settled in order of distance, then node number,
+// so when there are several shortest paths, the predecessor is the first settled node on one of them.
 func (g *Graph) Dijkstra(source int) (map[int]int, map[int]int) {
 	if source < 1 || source > g.Nodes {
 		panic(fmt.Sprintf("Dijkstra: source should be in range [1, %v], got %v", g.Nodes, source))
@@ -55,7 +56,12 @@ func (g *Graph) Dijkstra(source int) (map[int]int, map[int]int) {
 
 	// Main loop of Dijkstra's algorithm.
 	for prioQueue.Len() > 0 {
-		_, currNode, _ := prioQueue.PopMin()             // Extract the best node.
+		_, currNode, _ := prioQueue.PopMin() // Extract the best node.
+		// If the best node is unreachable, so are all the remaining ones. Stop here, as adding
+		// a weight to the 'Inf' distance would overflow.
+		if dist[currNode] == math.MaxInt {
+			break
+		}
 		for _, edge := range g.AdjacencyList[currNode] { // Go through all neighbors of currNode.
 			alt := dist[currNode] + edge.Weight // Calculate the alternative path distance.
 			// If the alt path is shorter than the previously known shortest path to `edge.To`, update the path.
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Error returned when an edge can't be added to a TopoGraph, because it would create a cycle.
+type CycleError struct {
+	Cycle []int // The cycle that the edge would close, eg. [3, 1, 2, 3] for an edge 3 -> 1.
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("ConnectNodes: edge %v->%v would create a cycle %v", e.Cycle[0], e.Cycle[1], e.Cycle)
+}
+
+// Directed acyclic graph that keeps a topological order of its nodes up to date while edges are added,
+// using the Pearce-Kelly algorithm. Adding an edge that agrees with the current order costs O(1).
+// Otherwise only the nodes between the endpoints of the edge in the order are searched and reordered,
+// instead of sorting the whole graph again with KahnTopoSort.
+type TopoGraph struct {
+	graph        Graph
+	predecessors map[int][]int // Reverse adjacency list, for the backward search.
+	position     []int         // position[node-1] is the index of the node in the order.
+	order        []int         // order[i] is the node at index i.
+}
+
+// Get a new empty TopoGraph.
+func NewTopoGraph() *TopoGraph {
+	return &TopoGraph{graph: NewEmptyGraph(true), predecessors: make(map[int][]int)}
+}
+
+// Get a new TopoGraph with a clone of `g`, ordered with KahnTopoSort.
+// Return the error of KahnTopoSort if `g` has a cycle.
+func NewTopoGraphFrom(g *Graph) (*TopoGraph, error) {
+	order, err := g.KahnTopoSort()
+	if err != nil {
+		return nil, err
+	}
+	t := NewTopoGraph()
+	t.graph = g.Clone()
+	t.position = make([]int, g.Nodes)
+	t.order = make([]int, g.Nodes)
+	for i, node := range order {
+		t.position[node-1] = i
+		t.order[i] = node
+	}
+	for _, edges := range g.AdjacencyList {
+		for _, edge := range edges {
+			t.predecessors[edge.To] = append(t.predecessors[edge.To], edge.From)
+		}
+	}
+	return t, nil
+}
+
+// Get the underlying graph. It must not be modified directly, as the order would get out of sync.
+func (t *TopoGraph) Graph() *Graph {
+	return &t.graph
+}
+
+// Get the current topological order of the nodes.
+func (t *TopoGraph) Order() []int {
+	return append([]int{}, t.order...)
+}
+
+// Add numNodes number of nodes, they are placed at the end of the order. Panic if numNodes less than one.
+func (t *TopoGraph) AddNodes(numNodes int) {
+	first := t.graph.Nodes + 1
+	t.graph.AddNodes(numNodes)
+	for node := first; node <= t.graph.Nodes; node++ {
+		t.position = append(t.position, len(t.order))
+		t.order = append(t.order, node)
+	}
+}
+
+// Connect node `from` with node `to`, keeping the topological order. If the edge would create a cycle,
+// the graph is not modified and a *CycleError with the cycle is returned.
+// Panic on invalid nodes or duplicate edges, same as Graph.ConnectNodes.
+//
+// If `from` is already before `to` in the order, nothing changes. Otherwise, only nodes with positions
+// between pos(to) and pos(from) can be affected. A forward search from `to` finds the affected nodes
+// reachable from it (if it reaches `from`, there's a cycle), and a backward search from `from` finds
+// the affected nodes that reach it. The backward nodes must now come before the forward ones, so both
+// groups are put, in that order, into the positions they occupied together.
+func (t *TopoGraph) ConnectNodes(from int, to int, weight int) error {
+	if from < 1 || from > t.graph.Nodes || to < 1 || to > t.graph.Nodes {
+		panic(fmt.Sprintf("ConnectNodes: from and to node should be in range [1, %v]", t.graph.Nodes))
+	}
+	if weight == 0 {
+		// Checked before reordering, so the order is left as it was.
+		panic("Weight of the connection should be non-zero")
+	}
+
+	lower, upper := t.position[to-1], t.position[from-1]
+	if lower < upper {
+		// Forward search, only through nodes not further than `from` in the order.
+		forward := []int{}
+		parent := map[int]int{to: 0} // To reconstruct the cycle.
+		stack := NewStack()
+		stack.Push(to)
+		for stack.Length() > 0 {
+			v := stack.Pop()
+			forward = append(forward, v)
+			for _, edge := range t.graph.AdjacencyList[v] {
+				if edge.To == from {
+					cycle := []int{from}
+					for node := v; node != 0; node = parent[node] {
+						cycle = append(cycle, node)
+					}
+					// The path to `from` was built backwards, the cycle starts with the new edge from -> to.
+					slices.Reverse(cycle[1:])
+					return &CycleError{Cycle: append(cycle, from)}
+				}
+				if _, seen := parent[edge.To]; !seen && t.position[edge.To-1] < upper {
+					parent[edge.To] = v
+					stack.Push(edge.To)
+				}
+			}
+		}
+
+		// Backward search, only through nodes not before `to` in the order.
+		backward := []int{}
+		seen := map[int]bool{from: true}
+		stack.Push(from)
+		for stack.Length() > 0 {
+			v := stack.Pop()
+			backward = append(backward, v)
+			for _, u := range t.predecessors[v] {
+				if !seen[u] && t.position[u-1] > lower {
+					seen[u] = true
+					stack.Push(u)
+				}
+			}
+		}
+
+		t.reorder(backward, forward)
+	}
+
+	t.graph.ConnectNodes(from, to, weight)
+	t.predecessors[to] = append(t.predecessors[to], from)
+	return nil
+}
+
+// Move all `backward` nodes before all `forward` nodes, keeping the relative order within each group,
+// and using only the positions that these nodes already had.
+func (t *TopoGraph) reorder(backward []int, forward []int) {
+	byPosition := func(nodes []int) {
+		sort.Slice(nodes, func(i, j int) bool { return t.position[nodes[i]-1] < t.position[nodes[j]-1] })
+	}
+	byPosition(backward)
+	byPosition(forward)
+
+	nodes := append(backward, forward...)
+	positions := make([]int, len(nodes))
+	for i, node := range nodes {
+		positions[i] = t.position[node-1]
+	}
+	sort.Ints(positions)
+
+	for i, node := range nodes {
+		t.position[node-1] = positions[i]
+		t.order[positions[i]] = node
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// Check that the order is a permutation of the nodes that respects every edge.
+func isTopologicalOrder(g *Graph, order []int) bool {
+	if len(order) != g.Nodes {
+		return false
+	}
+	position := make(map[int]int)
+	for i, node := range order {
+		if _, seen := position[node]; seen || node < 1 || node > g.Nodes {
+			return false
+		}
+		position[node] = i
+	}
+	for _, edge := range edgeList(g) {
+		if position[edge.From] >= position[edge.To] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTopoGraph(t *testing.T) {
+	tg := NewTopoGraph()
+	tg.AddNodes(4)
+	if !slicesEqual(tg.Order(), []int{1, 2, 3, 4}) {
+		t.Fatalf("Expected initial order [1 2 3 4], got %v", tg.Order())
+	}
+
+	for _, e := range [][2]int{{4, 3}, {3, 2}, {2, 1}} {
+		if err := tg.ConnectNodes(e[0], e[1], 1); err != nil {
+			t.Fatalf("ConnectNodes(%d, %d): unexpected error %v", e[0], e[1], err)
+		}
+	}
+	if !slicesEqual(tg.Order(), []int{4, 3, 2, 1}) {
+		t.Errorf("Expected order [4 3 2 1], got %v", tg.Order())
+	}
+
+	err := tg.ConnectNodes(1, 3, 1)
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a CycleError, got %v", err)
+	}
+	if !slicesEqual(cycleErr.Cycle, []int{1, 3, 2, 1}) {
+		t.Errorf("Expected cycle [1 3 2 1], got %v", cycleErr.Cycle)
+	}
+	if tg.Graph().edgeExists(1, 3) {
+		t.Errorf("Rejected edge was added to the graph")
+	}
+
+	// New nodes go to the end of the order.
+	tg.AddNodes(1)
+	if err := tg.ConnectNodes(5, 4, 1); err != nil || !isTopologicalOrder(tg.Graph(), tg.Order()) {
+		t.Errorf("Unexpected order %v after adding a new node, error %v", tg.Order(), err)
+	}
+}
+
+func TestNewTopoGraphFrom(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(3, 2, 1)
+	g.ConnectNodes(2, 1, 1)
+
+	tg, err := NewTopoGraphFrom(&g)
+	if err != nil || !slicesEqual(tg.Order(), []int{3, 2, 1}) {
+		t.Fatalf("NewTopoGraphFrom: got order %v, error %v", tg.Order(), err)
+	}
+	var cycleErr *CycleError
+	if err := tg.ConnectNodes(1, 3, 1); !errors.As(err, &cycleErr) || !slicesEqual(cycleErr.Cycle, []int{1, 3, 2, 1}) {
+		t.Errorf("Expected cycle [1 3 2 1], got %v", err)
+	}
+
+	cyclic := cyclicGraph()
+	if _, err := NewTopoGraphFrom(&cyclic); err == nil {
+		t.Errorf("Expected an error for a cyclic graph")
+	}
+}
+
+// A panic on a zero weight edge that goes against the order must leave the order as it was.
+func TestTopoGraphZeroWeight(t *testing.T) {
+	tg := NewTopoGraph()
+	tg.AddNodes(3)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected ConnectNodes to panic on zero weight")
+		}
+		if !slicesEqual(tg.Order(), []int{1, 2, 3}) {
+			t.Errorf("Expected order [1 2 3] after the panic, got %v", tg.Order())
+		}
+	}()
+	tg.ConnectNodes(3, 1, 0)
+}
+
+// Insert random edges one by one. After each insertion the order must be valid, and an edge must be
+// rejected exactly when it closes a cycle, which has to consist of existing edges plus the new one.
+func TestTopoGraphRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for i := 0; i < 100; i++ {
+		n := r.Intn(12) + 2
+		tg := NewTopoGraph()
+		tg.AddNodes(n)
+
+		for j := 0; j < 3*n; j++ {
+			from, to := r.Intn(n)+1, r.Intn(n)+1
+			if from == to || tg.Graph().edgeExists(from, to) {
+				continue
+			}
+			edges := append(edgeList(tg.Graph()), newEdge(from, to, 1))
+			createsCycle := hasCycle(n, edges)
+
+			err := tg.ConnectNodes(from, to, 1)
+			if createsCycle != (err != nil) {
+				t.Fatalf("ConnectNodes(%d, %d): cycle expected: %v, got error %v", from, to, createsCycle, err)
+			}
+			if err != nil {
+				cycle := err.(*CycleError).Cycle
+				if cycle[0] != from || cycle[1] != to || cycle[len(cycle)-1] != from {
+					t.Fatalf("ConnectNodes(%d, %d): cycle %v should start with the new edge", from, to, cycle)
+				}
+				for k := 1; k+1 < len(cycle); k++ {
+					if !tg.Graph().edgeExists(cycle[k], cycle[k+1]) {
+						t.Fatalf("ConnectNodes(%d, %d): cycle %v uses a missing edge", from, to, cycle)
+					}
+				}
+			}
+			if !isTopologicalOrder(tg.Graph(), tg.Order()) {
+				t.Fatalf("Invalid order %v for %v", tg.Order(), tg.Graph().AdjacencyList)
+			}
+		}
+	}
+}
@@ -14,8 +14,8 @@ type ResidualEdge struct {
 }
 
 type ResidualGraph struct {
-	Nodes         int // Number of nodes, the nodes are indexed from 1 to `Nodes`.
-	AdjacencyList map[int][]ResidualEdge
+	Nodes         int                     // Number of nodes, the nodes are indexed from 1 to `Nodes`.
+	AdjacencyList map[int][]*ResidualEdge // Pointers, so that the `Rev` links stay valid.
 }
 
 // Construct a ResidualGraph based on a regular graph. It is to make the Edmonds-Karp algorithms more clear.
@@ -23,19 +23,19 @@ type ResidualGraph struct {
 func constructResidualGraph(g *Graph) *ResidualGraph {
 	rg := &ResidualGraph{
 		Nodes:         g.Nodes,
-		AdjacencyList: make(map[int][]ResidualEdge),
+		AdjacencyList: make(map[int][]*ResidualEdge),
 	}
 
 	// Initialize the adjacency list for each node.
 	for i := 1; i <= g.Nodes; i++ {
-		rg.AdjacencyList[i] = []ResidualEdge{}
+		rg.AdjacencyList[i] = []*ResidualEdge{}
 	}
 
 	// Create forward and reverse edges for each edge in the original graph.
 	for _, edges := range g.AdjacencyList {
 		for _, edge := range edges {
 			// Create the forward edge
-			forward := ResidualEdge{
+			forward := &ResidualEdge{
 				From: edge.From,
 				To:   edge.To,
 				Cap:  edge.Weight, // Assuming original weight is the capacity.
@@ -43,14 +43,14 @@ func constructResidualGraph(g *Graph) *ResidualGraph {
 				Rev:  nil,         // Reverse edge, to be linked later.
 			}
 			// Create the reverse edge
-			reverse := ResidualEdge{
+			reverse := &ResidualEdge{
 				From: edge.To,
 				To:   edge.From,
 				Cap:  0, // Reverse edge initially has zero capacity.
 				Flow: 0,
-				Rev:  &forward,
+				Rev:  forward,
 			}
-			forward.Rev = &reverse
+			forward.Rev = reverse
 
 			// Add to the adjacency lists.
 			rg.AdjacencyList[edge.From] = append(rg.AdjacencyList[edge.From], forward)
@@ -87,8 +87,7 @@ func (g *Graph) EdmondsKarp(source int, sink int) int {
 		// A regular BFS loop.
 		for queue.Length() > 0 && path[sink] == nil {
 			curr := queue.Dequeue()
-			for i := range res.AdjacencyList[curr] {
-				edge := &res.AdjacencyList[curr][i]
+			for _, edge := range res.AdjacencyList[curr] {
 				if path[edge.To] == nil && edge.To != source && edge.Cap > edge.Flow {
 					path[edge.To] = edge
 					queue.Enqueue(edge.To)
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Check the degree conditions for an Eulerian path and pick the node where it should start.
+// Directed graphs: a circuit needs in-degree == out-degree for every node, a path allows one node
+// with one more outgoing edge (the start) and one with one more incoming edge (the end).
+// Undirected graphs: a circuit needs all degrees even, a path allows exactly two odd nodes.
+// Return the start node and whether the result will be a circuit. Errors are prefixed with `name`.
+func (g *Graph) eulerianStart(name string) (int, bool, error) {
+	start := min(1, g.Nodes) // Any node with edges, if the result is a circuit. Node 1 if there are no edges.
+	for node := 1; node <= g.Nodes; node++ {
+		if len(g.AdjacencyList[node]) > 0 {
+			start = node
+			break
+		}
+	}
+
+	if g.Directed {
+		inDegree := g.inDegree()
+		starts, ends := []int{}, []int{}
+		for node := 1; node <= g.Nodes; node++ {
+			out, in := len(g.AdjacencyList[node]), inDegree[node]
+			switch {
+			case out == in:
+			case out == in+1:
+				starts = append(starts, node)
+			case in == out+1:
+				ends = append(ends, node)
+			default:
+				return 0, false, fmt.Errorf("%s: node %d has in-degree %d and out-degree %d, they can differ by at most 1", name, node, in, out)
+			}
+		}
+		if len(starts) == 0 && len(ends) == 0 {
+			return start, true, nil
+		}
+		if len(starts) == 1 && len(ends) == 1 {
+			return starts[0], false, nil
+		}
+		return 0, false, fmt.Errorf("%s: expected one node with out-degree = in-degree + 1 and one with in-degree = out-degree + 1, got %v and %v", name, starts, ends)
+	}
+
+	odd := []int{}
+	for node := 1; node <= g.Nodes; node++ {
+		if len(g.AdjacencyList[node])%2 == 1 {
+			odd = append(odd, node)
+		}
+	}
+	switch len(odd) {
+	case 0:
+		return start, true, nil
+	case 2:
+		return odd[0], false, nil
+	default:
+		return 0, false, fmt.Errorf("%s: expected 0 or 2 nodes with odd degree, got %d: %v", name, len(odd), odd)
+	}
+}
+
+// Find a path that uses every edge exactly once, using Hierholzer's algorithm.
+// If possible, the path is a circuit (starts and ends in the same node). Return the nodes of the path,
+// or an error explaining which condition fails: wrong degrees, or edges in more than one component.
+// For a graph without edges the path is just node 1.
+//
+// Hierholzer's algorithm walks along unused edges until it gets stuck, which can only happen at the
+// end of the path. Then it backtracks, and from each node with unused edges it walks again, splicing
+// the new circuit into the path. With a stack this becomes a single loop.
+func (g *Graph) EulerianPath() ([]int, error) {
+	start, _, err := g.eulerianStart("EulerianPath")
+	if err != nil {
+		return nil, err
+	}
+	if start == 0 { // No nodes at all.
+		return []int{}, nil
+	}
+
+	// In undirected graphs every edge is stored twice. Give both directions the same id, so that
+	// using one direction marks the other one as used too.
+	edgeID := make(map[int][]int)
+	numEdges := 0
+	pairIDs := make(map[[2]int]int)
+	for node := 1; node <= g.Nodes; node++ {
+		for _, edge := range g.AdjacencyList[node] {
+			if !g.Directed {
+				key := [2]int{min(node, edge.To), max(node, edge.To)}
+				if id, exists := pairIDs[key]; exists {
+					edgeID[node] = append(edgeID[node], id)
+					continue
+				}
+				pairIDs[key] = numEdges
+			}
+			edgeID[node] = append(edgeID[node], numEdges)
+			numEdges++
+		}
+	}
+
+	used := make([]bool, numEdges)
+	next := make(map[int]int) // Index of the next edge to try in the adjacency list of each node.
+	stack := NewStack()
+	path := []int{}
+
+	stack.Push(start)
+	for stack.Length() > 0 {
+		v := stack.Peek()
+		// Skip edges already used from the other side.
+		for next[v] < len(g.AdjacencyList[v]) && used[edgeID[v][next[v]]] {
+			next[v]++
+		}
+		if next[v] == len(g.AdjacencyList[v]) {
+			// Stuck, v is the next node of the path, counting from the end.
+			path = append(path, stack.Pop())
+			continue
+		}
+		edge := g.AdjacencyList[v][next[v]]
+		used[edgeID[v][next[v]]] = true
+		stack.Push(edge.To)
+	}
+
+	if len(path) != numEdges+1 {
+		return nil, fmt.Errorf("EulerianPath: edges are not connected, only %d of %d edges can be reached from node %d", len(path)-1, numEdges, start)
+	}
+
+	slices.Reverse(path) // The path was built backwards.
+	return path, nil
+}
+
+// Find a circuit that uses every edge exactly once and returns to the starting node.
+// Return an error if there is no such circuit, even if an Eulerian path exists.
+func (g *Graph) EulerianCircuit() ([]int, error) {
+	_, circuit, err := g.eulerianStart("EulerianCircuit")
+	if err != nil {
+		return nil, err
+	}
+	if !circuit {
+		if g.Directed {
+			return nil, fmt.Errorf("EulerianCircuit: every node should have in-degree equal to out-degree")
+		}
+		return nil, fmt.Errorf("EulerianCircuit: every node should have an even degree")
+	}
+	return g.EulerianPath()
+}
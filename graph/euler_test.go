@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Check that the path uses every edge of the graph exactly once.
+func isEulerianPath(g *Graph, path []int) bool {
+	edges := edgeList(g)
+	if len(path) != len(edges)+1 {
+		return false
+	}
+	unused := make(map[string]int)
+	key := func(from, to int) string {
+		if !g.Directed && from > to {
+			from, to = to, from
+		}
+		return fmt.Sprintf("%d-%d", from, to)
+	}
+	for _, edge := range edges {
+		unused[key(edge.From, edge.To)]++
+	}
+	for i := 0; i+1 < len(path); i++ {
+		k := key(path[i], path[i+1])
+		if !g.edgeExists(path[i], path[i+1]) || unused[k] == 0 {
+			return false
+		}
+		unused[k]--
+	}
+	return true
+}
+
+func TestEulerianPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		directed bool
+		numNodes int
+		edges    [][2]int
+		circuit  bool // Whether an Eulerian circuit exists.
+		path     bool // Whether an Eulerian path exists.
+	}{
+		{"Undirected triangle", false, 3, [][2]int{{1, 2}, {2, 3}, {3, 1}}, true, true},
+		{"Undirected path", false, 3, [][2]int{{1, 2}, {2, 3}}, false, true},
+		{"House with two odd nodes", false, 5, [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 1}, {1, 5}, {2, 5}, {1, 3}}, false, true},
+		{"Undirected star", false, 4, [][2]int{{1, 2}, {1, 3}, {1, 4}}, false, false},
+		{"Two undirected triangles", false, 6, [][2]int{{1, 2}, {2, 3}, {3, 1}, {4, 5}, {5, 6}, {6, 4}}, false, false},
+		{"Directed cycle", true, 3, [][2]int{{1, 2}, {2, 3}, {3, 1}}, true, true},
+		{"Directed path", true, 3, [][2]int{{2, 3}, {3, 1}}, false, true},
+		{"Directed bow tie", true, 5, [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 3}}, true, true},
+		{"Directed two sources", true, 3, [][2]int{{1, 3}, {2, 3}}, false, false},
+		{"No edges", true, 3, [][2]int{}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewEmptyGraph(tt.directed)
+			g.AddNodes(tt.numNodes)
+			for _, edge := range tt.edges {
+				g.ConnectNodes(edge[0], edge[1], 1)
+			}
+
+			path, err := g.EulerianPath()
+			if tt.path != (err == nil) {
+				t.Fatalf("EulerianPath: expected path to exist: %v, got error %v", tt.path, err)
+			}
+			if err == nil && !isEulerianPath(&g, path) {
+				t.Errorf("EulerianPath: %v is not an Eulerian path", path)
+			}
+
+			circuit, err := g.EulerianCircuit()
+			if tt.circuit != (err == nil) {
+				t.Fatalf("EulerianCircuit: expected circuit to exist: %v, got error %v", tt.circuit, err)
+			}
+			if err == nil && (!isEulerianPath(&g, circuit) || (len(circuit) > 0 && circuit[0] != circuit[len(circuit)-1])) {
+				t.Errorf("EulerianCircuit: %v is not an Eulerian circuit", circuit)
+			}
+		})
+	}
+}
+
+// On random graphs, a path is returned whenever the degree and connectivity conditions hold.
+func TestEulerianPathRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	for i := 0; i < 300; i++ {
+		g := randomGraph(r, r.Intn(7)+1, i%2 == 0, r.Float64()*0.6, 1)
+		if len(edgeList(&g)) > 8 {
+			continue // The brute force below is exponential in the number of edges.
+		}
+		path, err := g.EulerianPath()
+		if err == nil && !isEulerianPath(&g, path) {
+			t.Fatalf("EulerianPath: %v is not an Eulerian path of %v", path, g.AdjacencyList)
+		}
+		if err != nil {
+			// Double check the failure by brute force: try to extend trails from every node.
+			if bruteForceEulerian(&g) {
+				t.Fatalf("EulerianPath: got error %v, but a path exists in %v", err, g.AdjacencyList)
+			}
+		}
+	}
+}
+
+// Try all trails from every node, return true if any of them uses every edge.
+func bruteForceEulerian(g *Graph) bool {
+	total := len(edgeList(g))
+	used := make(map[[2]int]bool)
+	var extend func(v int, length int) bool
+	extend = func(v int, length int) bool {
+		if length == total {
+			return true
+		}
+		for _, edge := range g.AdjacencyList[v] {
+			key := [2]int{v, edge.To}
+			if !g.Directed {
+				key = [2]int{min(v, edge.To), max(v, edge.To)}
+			}
+			if used[key] {
+				continue
+			}
+			used[key] = true
+			if extend(edge.To, length+1) {
+				return true
+			}
+			used[key] = false
+		}
+		return false
+	}
+	for node := 1; node <= g.Nodes; node++ {
+		if extend(node, 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+// Fuzz targets for the graph algorithms, checked against the oracles from oracle_test.go.
+// Under plain `go test` only the seed corpus is run, use `go test -fuzz=FuzzDijkstra` etc.
+// to explore further.
+
+// Decode fuzzer input into a small graph. The first byte sets the number of nodes,
+// every following triple of bytes is an edge (from, to, weight). Edges that would make
+// ConnectNodes panic (self loops, duplicates) are skipped. The node count is capped,
+// as the oracles are exponential in the number of nodes or edges.
+func graphFromBytes(data []byte, directed bool, maxNodes int, maxEdges int) Graph {
+	g := NewEmptyGraph(directed)
+	if len(data) == 0 {
+		g.AddNodes(1)
+		return g
+	}
+	g.AddNodes(int(data[0])%maxNodes + 1)
+
+	edges := 0
+	for i := 1; i+2 < len(data) && edges < maxEdges; i += 3 {
+		from := int(data[i])%g.Nodes + 1
+		to := int(data[i+1])%g.Nodes + 1
+		weight := int(data[i+2])%20 + 1
+		if from == to || g.edgeExists(from, to) {
+			continue
+		}
+		g.ConnectNodes(from, to, weight)
+		edges++
+	}
+	return g
+}
+
+func FuzzDijkstra(f *testing.F) {
+	f.Add([]byte{4, 0, 1, 3, 1, 2, 4, 0, 2, 9}, true)
+	f.Add([]byte{5, 0, 1, 1, 1, 2, 1, 3, 4, 1}, false)
+	f.Add([]byte{3, 2, 1, 5}, true)
+	f.Fuzz(func(t *testing.T, data []byte, directed bool) {
+		g := graphFromBytes(data, directed, 8, 30)
+		checkDijkstra(t, &g)
+	})
+}
+
+func FuzzEdmondsKarp(f *testing.F) {
+	f.Add([]byte{3, 0, 1, 10, 0, 2, 10, 1, 2, 1, 1, 3, 10, 2, 3, 10}, true)
+	f.Add([]byte{3, 0, 1, 3, 1, 2, 4, 2, 3, 5, 0, 3, 1}, false)
+	f.Add([]byte{5, 0, 1, 7, 2, 3, 7}, true)
+	f.Fuzz(func(t *testing.T, data []byte, directed bool) {
+		g := graphFromBytes(data, directed, 6, 20)
+		checkEdmondsKarp(t, &g)
+	})
+}
+
+func FuzzKruskalMST(f *testing.F) {
+	f.Add([]byte{3, 0, 1, 1, 1, 2, 2, 2, 3, 3, 0, 3, 4, 0, 2, 5})
+	f.Add([]byte{5, 0, 1, 4, 2, 3, 4})
+	f.Add([]byte{0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := graphFromBytes(data, false, 6, 12)
+		checkKruskalMST(t, &g)
+	})
+}
+
+func FuzzKahnTopoSort(f *testing.F) {
+	f.Add([]byte{4, 0, 1, 1, 1, 2, 1, 0, 2, 1, 2, 3, 1})
+	f.Add([]byte{2, 0, 1, 1, 1, 2, 1, 2, 0, 1})
+	f.Add([]byte{6})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := graphFromBytes(data, true, 8, 30)
+		checkKahnTopoSort(t, &g)
+	})
+}
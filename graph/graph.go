@@ -36,6 +36,20 @@ func NewEmptyGraph(directed bool) Graph {
 	}
 }
 
+// Get a new graph with `nodes` nodes and the given edges, eg. to turn the result of KruskalMST
+// into a tree. Every edge is added with ConnectNodes, so for an undirected graph each edge should
+// be listed in one direction only. Panic on invalid edges, same as ConnectNodes.
+func NewGraphFromEdges(nodes int, edges []Edge, directed bool) Graph {
+	g := NewEmptyGraph(directed)
+	if nodes > 0 {
+		g.AddNodes(nodes)
+	}
+	for _, edge := range edges {
+		g.ConnectNodes(edge.From, edge.To, edge.Weight)
+	}
+	return g
+}
+
 // Add numNodes number of nodes to the graph. Panic if numNodes less than one.
 func (g *Graph) AddNodes(numNodes int) {
 	if numNodes < 1 {
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Maximum number of nodes for the bitmask dynamic programming algorithms below.
+// They take O(2^n * n^2) time and O(2^n * n) memory, see their tables for the exact sizes.
+const maxBitmaskNodes = 18
+
+// Find a path that visits every node exactly once, using dynamic programming over subsets of nodes.
+// There's a path that visits exactly the nodes in `mask` and ends in v if v is the only node, or if
+// some neighbor u of v has such a path over mask \ {v}. The table stores one byte per mask and node,
+// so it takes 2^n * n bytes, under 5 MB for 18 nodes.
+// Return the path, or an error if there's none. Panic if the graph has more than 18 nodes.
+func (g *Graph) HamiltonianPath() ([]int, error) {
+	if g.Nodes > maxBitmaskNodes {
+		panic(fmt.Sprintf("HamiltonianPath: graph should have at most %v nodes, got %v", maxBitmaskNodes, g.Nodes))
+	}
+	if g.Nodes == 0 {
+		return []int{}, nil
+	}
+
+	n := g.Nodes
+	matrix := g.AdjacencyMatrix() // matrix[u][v] != 0 means there's an edge u -> v, nodes shifted by 1.
+	full := 1<<n - 1
+
+	// prev[mask*n+v] is the node before v on a path over `mask` that ends in v, or -1 if there's no
+	// such path. For single nodes, where there's no previous node, it's v itself.
+	prev := make([]int8, (1<<n)*n)
+	for i := range prev {
+		prev[i] = -1
+	}
+	for v := range n {
+		prev[(1<<v)*n+v] = int8(v)
+	}
+
+	for mask := 1; mask <= full; mask++ {
+		for v := range n {
+			if mask&(1<<v) == 0 || prev[mask*n+v] != -1 {
+				continue
+			}
+			rest := mask ^ (1 << v)
+			for u := range n {
+				if rest&(1<<u) != 0 && prev[rest*n+u] != -1 && matrix[u][v] != 0 {
+					prev[mask*n+v] = int8(u)
+					break
+				}
+			}
+		}
+	}
+
+	for end := range n {
+		if prev[full*n+end] != -1 {
+			return walkBack(func(mask, v int) int { return int(prev[mask*n+v]) }, full, end), nil
+		}
+	}
+	return nil, fmt.Errorf("HamiltonianPath: there is no path that visits every node exactly once")
+}
+
+// Reconstruct a path over the nodes in `mask` that ends in `end`, where prev(mask, v) is the node
+// before v. Return it with nodes shifted back by 1.
+func walkBack(prev func(mask int, v int) int, mask int, end int) []int {
+	path := []int{}
+	v := end
+	for mask != 0 {
+		path = append(path, v+1)
+		u := prev(mask, v)
+		mask ^= 1 << v
+		v = u
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Solve the travelling salesman problem with the Held-Karp algorithm: find the cheapest cycle that
+// visits every node exactly once. cost[mask][v] is the cheapest path that starts in node 1, visits
+// exactly the nodes in `mask` and ends in v. The tour is the best cost[all][v] plus the edge v -> 1.
+// Only masks with node 1 are stored, with 8 bytes for the cost and 1 for the previous node, so the
+// tables take 2^(n-1) * n * 9 bytes, about 21 MB for 18 nodes.
+//
+// Return the tour, starting and ending in node 1, and its total weight. Return an error if there's
+// no such cycle, which includes undirected graphs with 2 nodes, as the tour would use the same edge
+// twice. Panic if the graph has more than 18 nodes.
+func (g *Graph) TravelingSalesman() ([]int, int, error) {
+	if g.Nodes > maxBitmaskNodes {
+		panic(fmt.Sprintf("TravelingSalesman: graph should have at most %v nodes, got %v", maxBitmaskNodes, g.Nodes))
+	}
+	if g.Nodes < 2 {
+		return nil, 0, fmt.Errorf("TravelingSalesman: graph should have at least 2 nodes, got %v", g.Nodes)
+	}
+	if g.Nodes == 2 && !g.Directed {
+		return nil, 0, fmt.Errorf("TravelingSalesman: an undirected graph with 2 nodes has no cycle without repeating an edge")
+	}
+
+	n := g.Nodes
+	matrix := g.AdjacencyMatrix()
+	full := 1<<n - 1
+
+	// Masks containing node 1 are all odd, mask>>1 numbers them from 0.
+	index := func(mask int, v int) int { return (mask>>1)*n + v }
+	cost := make([]int, (1<<(n-1))*n)
+	prev := make([]int8, len(cost))
+	for i := range cost {
+		cost[i] = math.MaxInt
+		prev[i] = -1
+	}
+	cost[index(1, 0)] = 0 // The tour starts in node 1 (index 0).
+	prev[index(1, 0)] = 0
+
+	for mask := 1; mask <= full; mask += 2 {
+		for u := range n {
+			if cost[index(mask, u)] == math.MaxInt {
+				continue
+			}
+			for v := range n {
+				if mask&(1<<v) != 0 || matrix[u][v] == 0 {
+					continue
+				}
+				next := index(mask|1<<v, v)
+				if alt := cost[index(mask, u)] + matrix[u][v]; alt < cost[next] {
+					cost[next] = alt
+					prev[next] = int8(u)
+				}
+			}
+		}
+	}
+
+	best, last := math.MaxInt, -1
+	for v := 1; v < n; v++ {
+		if c := cost[index(full, v)]; c != math.MaxInt && matrix[v][0] != 0 && c+matrix[v][0] < best {
+			best = c + matrix[v][0]
+			last = v
+		}
+	}
+	if last == -1 {
+		return nil, 0, fmt.Errorf("TravelingSalesman: there is no cycle that visits every node exactly once")
+	}
+	tour := walkBack(func(mask, v int) int { return int(prev[index(mask, v)]) }, full, last)
+	return append(tour, 1), best, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Call `visit` for every permutation of 1..n.
+func permutations(n int, visit func(perm []int)) {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i + 1
+	}
+	var generate func(k int)
+	generate = func(k int) {
+		if k == n {
+			visit(perm)
+			return
+		}
+		for i := k; i < n; i++ {
+			perm[k], perm[i] = perm[i], perm[k]
+			generate(k + 1)
+			perm[k], perm[i] = perm[i], perm[k]
+		}
+	}
+	generate(0)
+}
+
+// Check that the path visits every node once and follows the edges of the graph.
+func isHamiltonianPath(g *Graph, path []int) bool {
+	if len(path) != g.Nodes {
+		return false
+	}
+	seen := make(map[int]bool)
+	for i, node := range path {
+		if seen[node] || node < 1 || node > g.Nodes {
+			return false
+		}
+		seen[node] = true
+		if i > 0 && !g.edgeExists(path[i-1], node) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHamiltonianPath(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for i := 0; i < 200; i++ {
+		g := randomGraph(r, r.Intn(7)+1, i%2 == 0, r.Float64()*0.7, 1)
+
+		exists := false
+		permutations(g.Nodes, func(perm []int) {
+			exists = exists || isHamiltonianPath(&g, perm)
+		})
+
+		path, err := g.HamiltonianPath()
+		if exists != (err == nil) {
+			t.Fatalf("HamiltonianPath: expected path to exist: %v, got error %v for %v", exists, err, g.AdjacencyList)
+		}
+		if err == nil && !isHamiltonianPath(&g, path) {
+			t.Fatalf("HamiltonianPath: %v is not a Hamiltonian path of %v", path, g.AdjacencyList)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected HamiltonianPath to panic on a big graph")
+		}
+	}()
+	big := NewEmptyGraph(false)
+	big.AddNodes(maxBitmaskNodes + 1)
+	big.HamiltonianPath()
+}
+
+func TestTravelingSalesman(t *testing.T) {
+	// Square with expensive diagonals, the best tour goes around.
+	g := NewEmptyGraph(false)
+	g.AddNodes(4)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 3, 2)
+	g.ConnectNodes(3, 4, 3)
+	g.ConnectNodes(4, 1, 4)
+	g.ConnectNodes(1, 3, 10)
+	g.ConnectNodes(2, 4, 10)
+
+	tour, cost, err := g.TravelingSalesman()
+	if err != nil || cost != 10 || len(tour) != 5 || tour[0] != 1 || tour[4] != 1 {
+		t.Errorf("TravelingSalesman = %v, %d, %v; want a tour of cost 10", tour, cost, err)
+	}
+
+	// With 2 nodes, an undirected tour would use the same edge twice, a directed one needs both edges.
+	pair := NewEmptyGraph(false)
+	pair.AddNodes(2)
+	pair.ConnectNodes(1, 2, 5)
+	if tour, _, err := pair.TravelingSalesman(); err == nil {
+		t.Errorf("TravelingSalesman on 2 undirected nodes = %v, want an error", tour)
+	}
+	pair = NewEmptyGraph(true)
+	pair.AddNodes(2)
+	pair.ConnectNodes(1, 2, 5)
+	pair.ConnectNodes(2, 1, 3)
+	if tour, cost, err := pair.TravelingSalesman(); err != nil || cost != 8 || !reflect.DeepEqual(tour, []int{1, 2, 1}) {
+		t.Errorf("TravelingSalesman on 2 directed nodes = %v, %d, %v; want [1 2 1], 8", tour, cost, err)
+	}
+
+	// Compare with brute force over all permutations on random directed graphs.
+	r := rand.New(rand.NewSource(10))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(6)+2, true, 0.4+r.Float64()*0.6, 20)
+		matrix := g.AdjacencyMatrix()
+
+		best := math.MaxInt
+		permutations(g.Nodes, func(perm []int) {
+			if perm[0] != 1 {
+				return
+			}
+			total := 0
+			for j := range perm {
+				w := matrix[perm[j]-1][perm[(j+1)%len(perm)]-1]
+				if w == 0 {
+					return
+				}
+				total += w
+			}
+			best = min(best, total)
+		})
+
+		tour, cost, err := g.TravelingSalesman()
+		if best == math.MaxInt {
+			if err == nil {
+				t.Fatalf("TravelingSalesman: expected an error, got tour %v", tour)
+			}
+			continue
+		}
+		if err != nil || cost != best {
+			t.Fatalf("TravelingSalesman = %v, %d, %v; want cost %d", tour, cost, err, best)
+		}
+		total := 0
+		for j := 0; j+1 < len(tour); j++ {
+			total += matrix[tour[j]-1][tour[j+1]-1]
+		}
+		if !isHamiltonianPath(&g, tour[:len(tour)-1]) || tour[len(tour)-1] != 1 || total != cost {
+			t.Fatalf("TravelingSalesman: %v is not a tour of cost %d", tour, cost)
+		}
+	}
+}
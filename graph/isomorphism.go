@@ -0,0 +1,316 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// State of the VF2 matching of the nodes of `pattern` to the nodes of `target`. The mapping is
+// extended one pair at a time, and undone when it can't be completed.
+type vf2State struct {
+	pattern, target *Graph
+	induced         bool // Whether non-edges of the pattern must map to non-edges of the target.
+
+	out1, in1, out2, in2 []map[int]bool // Successors and predecessors of every node, indexed by node.
+	core1, core2         []int          // core1[u] is the target node mapped to pattern node u, 0 if none.
+
+	// Number of mapped neighbors (in any direction) of every node. An unmapped node with a mapped
+	// neighbor is in the terminal set, the frontier of the current partial mapping.
+	terminal1, terminal2 []int
+
+	order   []int // Pattern nodes in the order they are matched.
+	parents []int // parents[u] is a pattern node matched before u and adjacent to it, 0 if none.
+}
+
+// Get the successors and predecessors of every node, for an undirected graph both are the neighbors.
+func (g *Graph) neighborMaps() ([]map[int]bool, []map[int]bool) {
+	out := make([]map[int]bool, g.Nodes+1)
+	in := make([]map[int]bool, g.Nodes+1)
+	for node := 1; node <= g.Nodes; node++ {
+		out[node] = make(map[int]bool)
+		in[node] = make(map[int]bool)
+	}
+	for node := 1; node <= g.Nodes; node++ {
+		for _, edge := range g.AdjacencyList[node] {
+			out[node][edge.To] = true
+			in[edge.To][node] = true
+		}
+	}
+	return out, in
+}
+
+func newVF2State(pattern *Graph, target *Graph, induced bool) *vf2State {
+	s := &vf2State{
+		pattern:   pattern,
+		target:    target,
+		induced:   induced,
+		core1:     make([]int, pattern.Nodes+1),
+		core2:     make([]int, target.Nodes+1),
+		terminal1: make([]int, pattern.Nodes+1),
+		terminal2: make([]int, target.Nodes+1),
+		parents:   make([]int, pattern.Nodes+1),
+	}
+	s.out1, s.in1 = pattern.neighborMaps()
+	s.out2, s.in2 = target.neighborMaps()
+
+	// Match the pattern in BFS order, starting each component from its node of highest degree and
+	// visiting neighbors with higher degree first. Constrained nodes early prune the search sooner,
+	// and every node after the first of a component has an already matched neighbor.
+	degree := func(u int) int { return len(s.out1[u]) + len(s.in1[u]) }
+	byDegree := make([]int, pattern.Nodes)
+	for i := range byDegree {
+		byDegree[i] = i + 1
+	}
+	sort.SliceStable(byDegree, func(i, j int) bool { return degree(byDegree[i]) > degree(byDegree[j]) })
+	seen := make([]bool, pattern.Nodes+1)
+	for _, start := range byDegree {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		queue := NewQueue()
+		queue.Enqueue(start)
+		for queue.Length() > 0 {
+			u := queue.Dequeue()
+			s.order = append(s.order, u)
+			neighbors := []int{}
+			for _, set := range []map[int]bool{s.out1[u], s.in1[u]} {
+				for v := range set {
+					if !seen[v] {
+						seen[v] = true
+						s.parents[v] = u
+						neighbors = append(neighbors, v)
+					}
+				}
+			}
+			sort.Slice(neighbors, func(i, j int) bool {
+				if degree(neighbors[i]) != degree(neighbors[j]) {
+					return degree(neighbors[i]) > degree(neighbors[j])
+				}
+				return neighbors[i] < neighbors[j]
+			})
+			for _, v := range neighbors {
+				queue.Enqueue(v)
+			}
+		}
+	}
+	return s
+}
+
+// Check if pattern node `u` can be mapped to target node `v`, given the current partial mapping.
+func (s *vf2State) feasible(u int, v int) bool {
+	if len(s.out2[v]) < len(s.out1[u]) || len(s.in2[v]) < len(s.in1[u]) {
+		return false
+	}
+	// Edges between u and the mapped nodes must exist between v and their images.
+	for w := range s.out1[u] {
+		if s.core1[w] != 0 && !s.out2[v][s.core1[w]] {
+			return false
+		}
+	}
+	for w := range s.in1[u] {
+		if s.core1[w] != 0 && !s.in2[v][s.core1[w]] {
+			return false
+		}
+	}
+	// For an induced match, also the other way around.
+	if s.induced {
+		for x := range s.out2[v] {
+			if s.core2[x] != 0 && !s.out1[u][s.core2[x]] {
+				return false
+			}
+		}
+		for x := range s.in2[v] {
+			if s.core2[x] != 0 && !s.in1[u][s.core2[x]] {
+				return false
+			}
+		}
+	}
+
+	// Look-ahead: unmapped neighbors of u in the terminal set must map to unmapped neighbors of v
+	// in the terminal set, and there must be enough unmapped neighbors of v for all of u's.
+	count := func(sets []map[int]bool, core []int, terminal []int) (int, int) {
+		inTerminal, unmapped := 0, 0
+		for _, set := range sets {
+			for w := range set {
+				if core[w] == 0 {
+					unmapped++
+					if terminal[w] > 0 {
+						inTerminal++
+					}
+				}
+			}
+		}
+		return inTerminal, unmapped
+	}
+	terminal1, unmapped1 := count([]map[int]bool{s.out1[u], s.in1[u]}, s.core1, s.terminal1)
+	terminal2, unmapped2 := count([]map[int]bool{s.out2[v], s.in2[v]}, s.core2, s.terminal2)
+	return terminal2 >= terminal1 && unmapped2 >= unmapped1
+}
+
+// Map pattern node `u` to target node `v`, or undo it with `delta` -1.
+func (s *vf2State) assign(u int, v int, delta int) {
+	if delta > 0 {
+		s.core1[u], s.core2[v] = v, u
+	} else {
+		s.core1[u], s.core2[v] = 0, 0
+	}
+	for _, set := range []map[int]bool{s.out1[u], s.in1[u]} {
+		for w := range set {
+			s.terminal1[w] += delta
+		}
+	}
+	for _, set := range []map[int]bool{s.out2[v], s.in2[v]} {
+		for x := range set {
+			s.terminal2[x] += delta
+		}
+	}
+}
+
+// Extend the mapping from the `depth`-th node of the order onwards. Return true once all pattern nodes
+// are mapped, leaving the mapping in core1.
+func (s *vf2State) match(depth int) bool {
+	if depth == len(s.order) {
+		return true
+	}
+	u := s.order[depth]
+
+	// A node with a matched neighbor can only map to a neighbor of that neighbor's image.
+	candidates := []int{}
+	if p := s.parents[u]; p != 0 {
+		for _, set := range []map[int]bool{s.out2[s.core1[p]], s.in2[s.core1[p]]} {
+			for v := range set {
+				candidates = append(candidates, v)
+			}
+		}
+		sort.Ints(candidates)
+		candidates = slices.Compact(candidates)
+	} else {
+		for v := 1; v <= s.target.Nodes; v++ {
+			candidates = append(candidates, v)
+		}
+	}
+
+	for _, v := range candidates {
+		if s.core2[v] != 0 || !s.feasible(u, v) {
+			continue
+		}
+		s.assign(u, v, 1)
+		if s.match(depth + 1) {
+			return true
+		}
+		s.assign(u, v, -1)
+	}
+	return false
+}
+
+// Get the mapping found by the state, from pattern nodes to target nodes.
+func (s *vf2State) mapping() map[int]int {
+	mapping := make(map[int]int)
+	for u := 1; u <= s.pattern.Nodes; u++ {
+		mapping[u] = s.core1[u]
+	}
+	return mapping
+}
+
+// Count the edges of a graph, undirected edges once.
+func (g *Graph) numEdges() int {
+	edges := 0
+	for _, edgeList := range g.AdjacencyList {
+		edges += len(edgeList)
+	}
+	if !g.Directed {
+		edges /= 2
+	}
+	return edges
+}
+
+// Check if two graphs are isomorphic with the VF2 algorithm, ie. if the nodes of `g` can be renamed
+// to get `other`. Weights are ignored, only the structure is compared. Return the mapping from the
+// nodes of `g` to the nodes of `other` and true, or nil and false if the graphs are not isomorphic.
+// Panic if one graph is directed and the other is not.
+//
+// VF2 builds the mapping one pair of nodes at a time with backtracking. A pair is added only if
+// the edges to the already mapped nodes match, and if the number of unmapped neighbors that are
+// adjacent to the mapped nodes (the terminal set) or not matches, which prunes most of the dead ends.
+func (g *Graph) Isomorphism(other *Graph) (map[int]int, bool) {
+	if g.Directed != other.Directed {
+		panic("Isomorphism: cannot compare a directed and an undirected graph.")
+	}
+	if g.Nodes != other.Nodes || g.numEdges() != other.numEdges() {
+		return nil, false
+	}
+	// With the same number of nodes and edges, an induced subgraph match covers the whole graph.
+	s := newVF2State(g, other, true)
+	if !s.match(0) {
+		return nil, false
+	}
+	return s.mapping(), true
+}
+
+// Search for a copy of `pattern` in `g` with the VF2 algorithm, see Isomorphism. Return a mapping
+// from the nodes of `pattern` to distinct nodes of `g` and true, or nil and false if there's none.
+// Every edge of the pattern must map to an edge of `g`. If `induced` is true, node pairs that are
+// not connected in the pattern must also not be connected in `g`, otherwise `g` may have extra edges.
+// Weights are ignored. Panic if one graph is directed and the other is not.
+func (g *Graph) SubgraphIsomorphism(pattern *Graph, induced bool) (map[int]int, bool) {
+	if g.Directed != pattern.Directed {
+		panic("SubgraphIsomorphism: cannot match a directed and an undirected graph.")
+	}
+	if pattern.Nodes > g.Nodes || pattern.numEdges() > g.numEdges() {
+		return nil, false
+	}
+	s := newVF2State(pattern, g, induced)
+	if !s.match(0) {
+		return nil, false
+	}
+	return s.mapping(), true
+}
+
+// Get a Weisfeiler-Lehman hash of the graph, for grouping graphs that are likely isomorphic.
+// Isomorphic graphs always have the same hash, but some non-isomorphic graphs do too (eg. regular
+// graphs with the same degree and number of nodes), so a match should be confirmed with Isomorphism.
+//
+// Every node starts with a label made of its in- and out-degree. In each of the `iterations` rounds,
+// the new label of a node is a hash of its label and the sorted labels of its successors and
+// predecessors. The result is a hash of the sorted labels of all rounds. Weights are ignored.
+func (g *Graph) WeisfeilerLehmanHash(iterations int) string {
+	if iterations < 0 {
+		panic(fmt.Sprintf("WeisfeilerLehmanHash: iterations should not be negative, got %v", iterations))
+	}
+	hash := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:8])
+	}
+	out, in := g.neighborMaps()
+
+	labels := make([]string, g.Nodes+1)
+	for node := 1; node <= g.Nodes; node++ {
+		labels[node] = fmt.Sprintf("%d,%d", len(out[node]), len(in[node]))
+	}
+	all := append([]string{}, labels[1:]...)
+
+	neighborLabels := func(set map[int]bool, labels []string) string {
+		result := []string{}
+		for v := range set {
+			result = append(result, labels[v])
+		}
+		sort.Strings(result)
+		return strings.Join(result, ",")
+	}
+	for range iterations {
+		next := make([]string, g.Nodes+1)
+		for node := 1; node <= g.Nodes; node++ {
+			next[node] = hash(labels[node] + "|" + neighborLabels(out[node], labels) + "|" + neighborLabels(in[node], labels))
+		}
+		labels = next
+		all = append(all, labels[1:]...)
+	}
+
+	sort.Strings(all)
+	return hash(fmt.Sprintf("%v|%v|%v", g.Directed, g.Nodes, strings.Join(all, ";")))
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Get a copy of the graph with nodes renamed by a permutation of 0..n-1, node v becomes perm[v-1]+1.
+// Edges are added in a random order, so the adjacency lists are shuffled too.
+func relabel(r *rand.Rand, g *Graph, perm []int) Graph {
+	relabeled := NewEmptyGraph(g.Directed)
+	if g.Nodes > 0 {
+		relabeled.AddNodes(g.Nodes)
+	}
+	edges := edgeList(g)
+	r.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	for _, edge := range edges {
+		relabeled.ConnectNodes(perm[edge.From-1]+1, perm[edge.To-1]+1, edge.Weight)
+	}
+	return relabeled
+}
+
+// Check that `mapping` is injective and maps every edge of `pattern` to an edge of `target`,
+// and if `induced`, every non-edge to a non-edge.
+func isValidMapping(pattern *Graph, target *Graph, mapping map[int]int, induced bool) bool {
+	if len(mapping) != pattern.Nodes {
+		return false
+	}
+	used := make(map[int]bool)
+	for u := 1; u <= pattern.Nodes; u++ {
+		v := mapping[u]
+		if v < 1 || v > target.Nodes || used[v] {
+			return false
+		}
+		used[v] = true
+	}
+	for a := 1; a <= pattern.Nodes; a++ {
+		for b := 1; b <= pattern.Nodes; b++ {
+			if a == b {
+				continue
+			}
+			edge, mapped := pattern.edgeExists(a, b), target.edgeExists(mapping[a], mapping[b])
+			if edge && !mapped || induced && !edge && mapped {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Check if there's a valid mapping from `pattern` to `target` by trying all injective mappings.
+func bruteForceSubgraph(pattern *Graph, target *Graph, induced bool) bool {
+	mapping := make(map[int]int)
+	used := make(map[int]bool)
+	var try func(u int) bool
+	try = func(u int) bool {
+		if u > pattern.Nodes {
+			return isValidMapping(pattern, target, mapping, induced)
+		}
+		for v := 1; v <= target.Nodes; v++ {
+			if !used[v] {
+				used[v], mapping[u] = true, v
+				if try(u + 1) {
+					return true
+				}
+				used[v] = false
+			}
+		}
+		delete(mapping, u)
+		return false
+	}
+	return try(1)
+}
+
+func TestIsomorphism(t *testing.T) {
+	// A 6-cycle and two triangles: both are 2-regular with 6 nodes and 6 edges,
+	// Weisfeiler-Lehman can't tell them apart, VF2 can.
+	cycle := NewEmptyGraph(false)
+	cycle.AddNodes(6)
+	for node := 1; node <= 6; node++ {
+		cycle.ConnectNodes(node, node%6+1, 1)
+	}
+	triangles := NewEmptyGraph(false)
+	triangles.AddNodes(6)
+	for _, edge := range [][2]int{{1, 2}, {2, 3}, {3, 1}, {4, 5}, {5, 6}, {6, 4}} {
+		triangles.ConnectNodes(edge[0], edge[1], 1)
+	}
+	if _, ok := cycle.Isomorphism(&triangles); ok {
+		t.Errorf("Expected a 6-cycle and two triangles not to be isomorphic")
+	}
+	if cycle.WeisfeilerLehmanHash(3) != triangles.WeisfeilerLehmanHash(3) {
+		t.Errorf("Expected Weisfeiler-Lehman hashes of regular graphs of the same size to collide")
+	}
+
+	// Weights don't matter.
+	shifted := NewEmptyGraph(false)
+	shifted.AddNodes(6)
+	for node := 1; node <= 6; node++ {
+		shifted.ConnectNodes(node%6+1, (node+1)%6+1, node)
+	}
+	mapping, ok := cycle.Isomorphism(&shifted)
+	if !ok || !isValidMapping(&cycle, &shifted, mapping, true) {
+		t.Errorf("Expected cycles to be isomorphic, got %v, %v", mapping, ok)
+	}
+
+	// A directed path and its reverse are isomorphic, but not with the edges pointing towards the middle.
+	path := NewEmptyGraph(true)
+	path.AddNodes(3)
+	path.ConnectNodes(1, 2, 1)
+	path.ConnectNodes(2, 3, 1)
+	reverse := path.Transpose()
+	inward := NewEmptyGraph(true)
+	inward.AddNodes(3)
+	inward.ConnectNodes(1, 2, 1)
+	inward.ConnectNodes(3, 2, 1)
+	if mapping, ok := path.Isomorphism(&reverse); !ok || mapping[1] != 3 || mapping[2] != 2 || mapping[3] != 1 {
+		t.Errorf("Expected mapping 1->3, 2->2, 3->1, got %v, %v", mapping, ok)
+	}
+	if _, ok := path.Isomorphism(&inward); ok {
+		t.Errorf("Expected a directed path not to be isomorphic to %v", inward.AdjacencyList)
+	}
+	if path.WeisfeilerLehmanHash(2) == inward.WeisfeilerLehmanHash(2) {
+		t.Errorf("Expected different hashes for a directed path and %v", inward.AdjacencyList)
+	}
+
+	empty := NewEmptyGraph(true)
+	if mapping, ok := empty.Isomorphism(&empty); !ok || len(mapping) != 0 {
+		t.Errorf("Expected empty graphs to be isomorphic, got %v, %v", mapping, ok)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected Isomorphism to panic on a directed and an undirected graph")
+		}
+	}()
+	cycle.Isomorphism(&path)
+}
+
+// Randomly relabeled graphs are isomorphic and have the same hash, and for small graphs the answer
+// matches brute force.
+func TestIsomorphismRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(38))
+	for i := 0; i < 200; i++ {
+		directed := i%2 == 0
+		g := randomGraph(r, r.Intn(12)+1, directed, r.Float64(), 5)
+		other := relabel(r, &g, r.Perm(g.Nodes))
+
+		mapping, ok := g.Isomorphism(&other)
+		if !ok || !isValidMapping(&g, &other, mapping, true) {
+			t.Fatalf("Expected %v and %v to be isomorphic, got %v, %v", g.AdjacencyList, other.AdjacencyList, mapping, ok)
+		}
+		if g.WeisfeilerLehmanHash(3) != other.WeisfeilerLehmanHash(3) {
+			t.Fatalf("Expected isomorphic graphs to have the same hash")
+		}
+
+		small := randomGraph(r, r.Intn(6)+1, directed, r.Float64(), 1)
+		another := randomGraph(r, small.Nodes, directed, r.Float64(), 1)
+		_, ok = small.Isomorphism(&another)
+		if expected := small.numEdges() == another.numEdges() && bruteForceSubgraph(&small, &another, true); ok != expected {
+			t.Fatalf("Isomorphism(%v, %v) = %v, want %v", small.AdjacencyList, another.AdjacencyList, ok, expected)
+		}
+	}
+}
+
+func TestSubgraphIsomorphism(t *testing.T) {
+	// A square with one diagonal contains a triangle, and a path of length 3 both induced and not.
+	// The square without the diagonal is only a non-induced subgraph.
+	g := NewEmptyGraph(false)
+	g.AddNodes(4)
+	for _, edge := range [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 1}, {1, 3}} {
+		g.ConnectNodes(edge[0], edge[1], 1)
+	}
+	square := NewEmptyGraph(false)
+	square.AddNodes(4)
+	for node := 1; node <= 4; node++ {
+		square.ConnectNodes(node, node%4+1, 1)
+	}
+	if mapping, ok := g.SubgraphIsomorphism(&square, false); !ok || !isValidMapping(&square, &g, mapping, false) {
+		t.Errorf("Expected the square to be a subgraph, got %v, %v", mapping, ok)
+	}
+	if _, ok := g.SubgraphIsomorphism(&square, true); ok {
+		t.Errorf("Expected the square not to be an induced subgraph")
+	}
+
+	triangle := NewEmptyGraph(false)
+	triangle.AddNodes(3)
+	for _, edge := range [][2]int{{1, 2}, {2, 3}, {3, 1}} {
+		triangle.ConnectNodes(edge[0], edge[1], 1)
+	}
+	if mapping, ok := g.SubgraphIsomorphism(&triangle, true); !ok || !isValidMapping(&triangle, &g, mapping, true) {
+		t.Errorf("Expected a triangle to be an induced subgraph, got %v, %v", mapping, ok)
+	}
+	if _, ok := square.SubgraphIsomorphism(&triangle, false); ok {
+		t.Errorf("Expected no triangle in a square")
+	}
+}
+
+func TestSubgraphIsomorphismRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(39))
+	for i := 0; i < 300; i++ {
+		directed := i%2 == 0
+		induced := i%3 == 0
+		g := randomGraph(r, r.Intn(7)+1, directed, r.Float64(), 1)
+		pattern := randomGraph(r, r.Intn(g.Nodes)+1, directed, r.Float64(), 1)
+
+		mapping, ok := g.SubgraphIsomorphism(&pattern, induced)
+		if expected := bruteForceSubgraph(&pattern, &g, induced); ok != expected {
+			t.Fatalf("SubgraphIsomorphism(%v, %v, induced %v) = %v, want %v", g.AdjacencyList, pattern.AdjacencyList, induced, ok, expected)
+		}
+		if ok && !isValidMapping(&pattern, &g, mapping, induced) {
+			t.Fatalf("SubgraphIsomorphism returned an invalid mapping %v", mapping)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Get a new graph from an adjacency matrix, the inverse of AdjacencyMatrix. matrix[i][j] is the weight
+// of the edge from node i+1 to node j+1, or `absent` if there's no such edge. AdjacencyMatrix uses 0
+// for missing edges, a different sentinel (eg. math.MinInt) can be used for matrices where 0 means
+// something else. Edges are added in row order, so the adjacency lists are sorted by target node.
+//
+// Return an error if the matrix is not square, has an edge on the diagonal or a weight of 0 for an
+// edge (self-loops and zero weights are not allowed by ConnectNodes), or, for undirected graphs,
+// if the matrix is not symmetric.
+func NewGraphFromMatrix(matrix [][]int, directed bool, absent int) (Graph, error) {
+	n := len(matrix)
+	for i, row := range matrix {
+		if len(row) != n {
+			return Graph{}, fmt.Errorf("NewGraphFromMatrix: matrix should be square, row %v has %v columns instead of %v", i, len(row), n)
+		}
+	}
+	for i := range n {
+		if matrix[i][i] != absent {
+			return Graph{}, fmt.Errorf("NewGraphFromMatrix: self-loops are not allowed, got %v at [%v][%v]", matrix[i][i], i, i)
+		}
+		for j := range n {
+			if matrix[i][j] == 0 && absent != 0 {
+				return Graph{}, fmt.Errorf("NewGraphFromMatrix: edge weights should be non-zero, got 0 at [%v][%v]", i, j)
+			}
+			if !directed && matrix[i][j] != matrix[j][i] {
+				return Graph{}, fmt.Errorf("NewGraphFromMatrix: matrix of an undirected graph should be symmetric, got %v at [%v][%v] and %v at [%v][%v]",
+					matrix[i][j], i, j, matrix[j][i], j, i)
+			}
+		}
+	}
+
+	g := NewEmptyGraph(directed)
+	if n > 0 {
+		g.AddNodes(n)
+	}
+	for i := range n {
+		for j := range n {
+			if matrix[i][j] != absent && (directed || i < j) {
+				g.ConnectNodes(i+1, j+1, matrix[i][j])
+			}
+		}
+	}
+	if !directed {
+		// Connecting i < j first would put the edges to smaller nodes at the end of the lists.
+		for node := 1; node <= n; node++ {
+			edges := g.AdjacencyList[node]
+			sort.Slice(edges, func(a, b int) bool { return edges[a].To < edges[b].To })
+		}
+	}
+	return g, nil
+}
+
+// Get the adjacency matrix in coordinate (COO) format: the edge from node rows[k]+1 to node cols[k]+1
+// has weight values[k]. Indices start from 0, as in AdjacencyMatrix. Undirected edges appear in both
+// directions, so the matrix is symmetric. Entries are sorted by row, then by column.
+func (g *Graph) COO() (rows []int, cols []int, values []int) {
+	rows, cols, values = []int{}, []int{}, []int{}
+	rowPtr, colIndices, csrValues := g.CSR()
+	for row := range g.Nodes {
+		for k := rowPtr[row]; k < rowPtr[row+1]; k++ {
+			rows = append(rows, row)
+			cols = append(cols, colIndices[k])
+			values = append(values, csrValues[k])
+		}
+	}
+	return rows, cols, values
+}
+
+// Get the adjacency matrix in compressed sparse row (CSR) format: the edges of node i+1 are at
+// positions rowPtr[i] to rowPtr[i+1]-1 of colIndices (target nodes, starting from 0) and values
+// (weights). rowPtr has Nodes+1 entries, the last one is the number of entries. Columns within
+// a row are sorted. Undirected edges appear in both directions.
+func (g *Graph) CSR() (rowPtr []int, colIndices []int, values []int) {
+	rowPtr = make([]int, g.Nodes+1)
+	colIndices, values = []int{}, []int{}
+	for node := 1; node <= g.Nodes; node++ {
+		edges := append([]Edge{}, g.AdjacencyList[node]...)
+		sort.Slice(edges, func(a, b int) bool { return edges[a].To < edges[b].To })
+		for _, edge := range edges {
+			colIndices = append(colIndices, edge.To-1)
+			values = append(values, edge.Weight)
+		}
+		rowPtr[node] = len(colIndices)
+	}
+	return rowPtr, colIndices, values
+}
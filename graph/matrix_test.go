@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNewGraphFromMatrix(t *testing.T) {
+	matrix := [][]int{
+		{0, 3, 0},
+		{0, 0, -2},
+		{7, 0, 0},
+	}
+	g, err := NewGraphFromMatrix(matrix, true, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := map[int][]Edge{
+		1: {{From: 1, To: 2, Weight: 3}},
+		2: {{From: 2, To: 3, Weight: -2}},
+		3: {{From: 3, To: 1, Weight: 7}},
+	}
+	if g.Nodes != 3 || !g.Directed || !reflect.DeepEqual(g.AdjacencyList, expected) {
+		t.Errorf("Expected %v, got %v", expected, g.AdjacencyList)
+	}
+
+	// With a different sentinel, 0 is no longer an absent edge, but it's not a valid weight either.
+	absent := math.MinInt
+	sentinel := [][]int{
+		{absent, 5},
+		{5, absent},
+	}
+	g, err = NewGraphFromMatrix(sentinel, false, absent)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if w, exists := g.edgeWeight(2, 1); !exists || w != 5 {
+		t.Errorf("Expected an edge 2-1 with weight 5, got %v, %v", w, exists)
+	}
+	if _, err := NewGraphFromMatrix(matrix, true, absent); err == nil {
+		t.Errorf("Expected an error for a zero weight with sentinel %v", absent)
+	}
+
+	empty, err := NewGraphFromMatrix([][]int{}, false, 0)
+	if err != nil || empty.Nodes != 0 {
+		t.Errorf("Expected an empty graph, got %v, %v", empty, err)
+	}
+}
+
+func TestNewGraphFromMatrixErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   [][]int
+		directed bool
+	}{
+		{"Not square", [][]int{{0, 1}, {1}}, true},
+		{"Self-loop", [][]int{{1, 0}, {0, 0}}, true},
+		{"Not symmetric", [][]int{{0, 1}, {2, 0}}, false},
+		{"One direction only", [][]int{{0, 1}, {0, 0}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGraphFromMatrix(tt.matrix, tt.directed, 0); err == nil {
+				t.Errorf("Expected an error for %v", tt.matrix)
+			}
+		})
+	}
+}
+
+// Going to a matrix and back gives the same graph, and the sparse formats have the same entries
+// as the dense matrix.
+func TestMatrixRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(39))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(10)+1, i%2 == 0, r.Float64(), 20)
+		matrix := g.AdjacencyMatrix()
+		back, err := NewGraphFromMatrix(matrix, g.Directed, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(back.AdjacencyMatrix(), matrix) {
+			t.Fatalf("Expected %v, got %v", matrix, back.AdjacencyMatrix())
+		}
+
+		rows, cols, values := g.COO()
+		dense := make([][]int, g.Nodes)
+		for row := range dense {
+			dense[row] = make([]int, g.Nodes)
+		}
+		for k := range rows {
+			dense[rows[k]][cols[k]] = values[k]
+			if k > 0 && (rows[k] < rows[k-1] || rows[k] == rows[k-1] && cols[k] <= cols[k-1]) {
+				t.Fatalf("COO entries are not sorted: %v, %v", rows, cols)
+			}
+		}
+		if !reflect.DeepEqual(dense, matrix) {
+			t.Fatalf("COO: expected %v, got %v", matrix, dense)
+		}
+
+		rowPtr, colIndices, csrValues := g.CSR()
+		if len(rowPtr) != g.Nodes+1 || rowPtr[g.Nodes] != len(values) {
+			t.Fatalf("CSR: unexpected rowPtr %v for %d entries", rowPtr, len(values))
+		}
+		for row := range g.Nodes {
+			for k := rowPtr[row]; k < rowPtr[row+1]; k++ {
+				if rows[k] != row || cols[k] != colIndices[k] || values[k] != csrValues[k] {
+					t.Fatalf("CSR entry %d doesn't match COO", k)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Reference oracles for the graph algorithms. Each oracle is a slow, obviously correct
+// (usually brute force) version of an algorithm, and the tests below compare the real
+// implementations against them on many small random graphs. The same checks are reused
+// by the fuzz targets in fuzz_test.go.
+
+// Get a random graph with `nodes` nodes. Every possible edge is added with probability `density`,
+// weights are drawn from [1, maxWeight].
+func randomGraph(r *rand.Rand, nodes int, directed bool, density float64, maxWeight int) Graph {
+	g := NewEmptyGraph(directed)
+	g.AddNodes(nodes)
+	for from := 1; from <= nodes; from++ {
+		for to := 1; to <= nodes; to++ {
+			if from == to || (!directed && from > to) {
+				continue
+			}
+			if r.Float64() < density {
+				g.ConnectNodes(from, to, r.Intn(maxWeight)+1)
+			}
+		}
+	}
+	return g
+}
+
+// Get all edges of a graph as a flat slice. For undirected graphs every edge is reported once,
+// with From < To.
+func edgeList(g *Graph) []Edge {
+	edges := []Edge{}
+	for node := 1; node <= g.Nodes; node++ {
+		for _, edge := range g.AdjacencyList[node] {
+			if !g.Directed && edge.From > edge.To {
+				continue
+			}
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Floyd-Warshall all-pairs shortest paths, dist[i][j] is math.MaxInt when j is unreachable from i.
+// Nodes are shifted by 1, as in AdjacencyMatrix().
+func floydWarshall(g *Graph) [][]int {
+	dist := make([][]int, g.Nodes)
+	for i := range dist {
+		dist[i] = make([]int, g.Nodes)
+		for j := range dist[i] {
+			dist[i][j] = math.MaxInt
+		}
+		dist[i][i] = 0
+	}
+	for _, edge := range edgeList(g) {
+		dist[edge.From-1][edge.To-1] = edge.Weight
+		if !g.Directed {
+			dist[edge.To-1][edge.From-1] = edge.Weight
+		}
+	}
+	for k := range g.Nodes {
+		for i := range g.Nodes {
+			for j := range g.Nodes {
+				if dist[i][k] == math.MaxInt || dist[k][j] == math.MaxInt {
+					continue
+				}
+				if dist[i][k]+dist[k][j] < dist[i][j] {
+					dist[i][j] = dist[i][k] + dist[k][j]
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// Compare Dijkstra's distances with Floyd-Warshall, and check that every predecessor
+// lies on a shortest path. Only valid for graphs with positive weights.
+func checkDijkstra(t *testing.T, g *Graph) {
+	t.Helper()
+	expected := floydWarshall(g)
+	matrix := g.AdjacencyMatrix()
+	for source := 1; source <= g.Nodes; source++ {
+		dist, prev := g.Dijkstra(source)
+		for node := 1; node <= g.Nodes; node++ {
+			if dist[node] != expected[source-1][node-1] {
+				t.Fatalf("Dijkstra(%d): distance to %d is %d, want %d", source, node, dist[node], expected[source-1][node-1])
+			}
+			if node == source || dist[node] == math.MaxInt {
+				continue
+			}
+			p := prev[node]
+			if p < 1 || p > g.Nodes || matrix[p-1][node-1] == 0 || dist[p]+matrix[p-1][node-1] != dist[node] {
+				t.Fatalf("Dijkstra(%d): predecessor %d of %d is not on a shortest path", source, p, node)
+			}
+		}
+	}
+}
+
+// Get the capacity of the minimum s-t cut by trying every subset of nodes that contains
+// the source and doesn't contain the sink. By the max-flow min-cut theorem, it equals the max flow.
+func minCut(g *Graph, source int, sink int) int {
+	edges := edgeList(g)
+	best := math.MaxInt
+	for mask := 0; mask < 1<<g.Nodes; mask++ {
+		inS := func(node int) bool { return mask&(1<<(node-1)) != 0 }
+		if !inS(source) || inS(sink) {
+			continue
+		}
+		cut := 0
+		for _, edge := range edges {
+			if inS(edge.From) && !inS(edge.To) {
+				cut += edge.Weight
+			}
+			if !g.Directed && inS(edge.To) && !inS(edge.From) {
+				cut += edge.Weight
+			}
+		}
+		best = min(best, cut)
+	}
+	return best
+}
+
+func checkEdmondsKarp(t *testing.T, g *Graph) {
+	t.Helper()
+	for source := 1; source <= g.Nodes; source++ {
+		for sink := 1; sink <= g.Nodes; sink++ {
+			if source == sink {
+				continue
+			}
+			flow := g.EdmondsKarp(source, sink)
+			if cut := minCut(g, source, sink); flow != cut {
+				t.Fatalf("EdmondsKarp(%d, %d) = %d, but the minimum cut is %d", source, sink, flow, cut)
+			}
+		}
+	}
+}
+
+// Get the number of connected components, treating every edge as undirected.
+func countComponents(nodes int, edges []Edge) int {
+	uf := NewUnionFind(nodes)
+	for _, edge := range edges {
+		uf.Union(edge.From-1, edge.To-1)
+	}
+	return uf.numSets
+}
+
+// Get the weight of the minimum spanning forest by trying every subset of edges.
+// A spanning forest has exactly nodes-components edges and no cycles.
+func bruteForceMSTWeight(nodes int, edges []Edge) int {
+	want := nodes - countComponents(nodes, edges)
+	best := math.MaxInt
+	for mask := 0; mask < 1<<len(edges); mask++ {
+		subset := []Edge{}
+		weight := 0
+		for i, edge := range edges {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, edge)
+				weight += edge.Weight
+			}
+		}
+		// Having nodes-components edges and the same number of components means there are no cycles.
+		if len(subset) == want && countComponents(nodes, subset) == nodes-want {
+			best = min(best, weight)
+		}
+	}
+	return best
+}
+
+// Check that the MST is a spanning forest of the graph with a minimum total weight.
+func checkKruskalMST(t *testing.T, g *Graph) {
+	t.Helper()
+	edges := edgeList(g)
+	mst := g.KruskalMST()
+
+	weight := 0
+	for _, edge := range mst {
+		if !g.edgeExists(edge.From, edge.To) {
+			t.Fatalf("KruskalMST: edge %v is not in the graph", edge)
+		}
+		weight += edge.Weight
+	}
+	components := countComponents(g.Nodes, edges)
+	if len(mst) != g.Nodes-components {
+		t.Fatalf("KruskalMST: got %d edges, want %d", len(mst), g.Nodes-components)
+	}
+	if countComponents(g.Nodes, mst) != components {
+		t.Fatalf("KruskalMST: %v does not span all the components of the graph", mst)
+	}
+	if expected := bruteForceMSTWeight(g.Nodes, edges); weight != expected {
+		t.Fatalf("KruskalMST: total weight is %d, want %d", weight, expected)
+	}
+}
+
+// Check if a directed graph has a cycle, using a recursive DFS with node colors.
+func hasCycle(nodes int, edges []Edge) bool {
+	adj := make(map[int][]int)
+	for _, edge := range edges {
+		adj[edge.From] = append(adj[edge.From], edge.To)
+	}
+	const (
+		white = iota // Not visited yet.
+		gray         // On the current DFS path.
+		black        // Fully processed.
+	)
+	color := make([]int, nodes+1)
+	var visit func(v int) bool
+	visit = func(v int) bool {
+		color[v] = gray
+		for _, u := range adj[v] {
+			if color[u] == gray || (color[u] == white && visit(u)) {
+				return true
+			}
+		}
+		color[v] = black
+		return false
+	}
+	for v := 1; v <= nodes; v++ {
+		if color[v] == white && visit(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check that the topological order is a permutation of all nodes which respects every edge,
+// or that an error is returned exactly when the graph has a cycle.
+func checkKahnTopoSort(t *testing.T, g *Graph) {
+	t.Helper()
+	edges := edgeList(g)
+	order, err := g.KahnTopoSort()
+	if len(edgeList(g)) != len(edges) {
+		t.Fatalf("KahnTopoSort: edges were removed from the original graph")
+	}
+
+	if hasCycle(g.Nodes, edges) {
+		if err == nil {
+			t.Fatalf("KahnTopoSort: expected a cycle error, got order %v", order)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("KahnTopoSort: unexpected error %v", err)
+	}
+	if len(order) != g.Nodes {
+		t.Fatalf("KahnTopoSort: got %d nodes, want %d", len(order), g.Nodes)
+	}
+	position := make(map[int]int)
+	for i, node := range order {
+		if _, seen := position[node]; seen || node < 1 || node > g.Nodes {
+			t.Fatalf("KahnTopoSort: %v is not a permutation of the nodes", order)
+		}
+		position[node] = i
+	}
+	for _, edge := range edges {
+		if position[edge.From] >= position[edge.To] {
+			t.Fatalf("KahnTopoSort: edge %d->%d is violated by %v", edge.From, edge.To, order)
+		}
+	}
+}
+
+func TestDijkstraOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		g := randomGraph(r, r.Intn(8)+1, i%2 == 0, r.Float64(), 20)
+		checkDijkstra(t, &g)
+	}
+}
+
+func TestEdmondsKarpOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		g := randomGraph(r, r.Intn(6)+2, i%2 == 0, r.Float64(), 20)
+		checkEdmondsKarp(t, &g)
+	}
+}
+
+func TestKruskalMSTOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		// Keep the graphs sparse, the brute force goes through every subset of edges.
+		g := randomGraph(r, r.Intn(6)+1, false, 0.5, 10)
+		if len(edgeList(&g)) > 12 {
+			continue
+		}
+		checkKruskalMST(t, &g)
+	}
+}
+
+func TestKahnTopoSortOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 200; i++ {
+		// Sparse graphs are more likely to be acyclic, dense ones to have cycles.
+		g := randomGraph(r, r.Intn(8)+1, true, r.Float64()*0.4, 5)
+		checkKahnTopoSort(t, &g)
+	}
+}
+
+// Sanity check for the oracles themselves, on a graph with known answers.
+func TestOracles(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(4)
+	g.ConnectNodes(1, 2, 3)
+	g.ConnectNodes(1, 3, 2)
+	g.ConnectNodes(2, 4, 2)
+	g.ConnectNodes(3, 4, 3)
+	g.ConnectNodes(2, 3, 1)
+
+	if dist := floydWarshall(&g); dist[0][3] != 5 || dist[3][0] != math.MaxInt {
+		t.Errorf("floydWarshall: got %v", dist)
+	}
+	if cut := minCut(&g, 1, 4); cut != 5 {
+		t.Errorf("minCut(1, 4) = %d, want 5", cut)
+	}
+	if hasCycle(g.Nodes, edgeList(&g)) {
+		t.Errorf("hasCycle: expected no cycle")
+	}
+	if !hasCycle(3, []Edge{newEdge(1, 2, 1), newEdge(2, 3, 1), newEdge(3, 1, 1)}) {
+		t.Errorf("hasCycle: expected a cycle")
+	}
+
+	edges := []Edge{newEdge(1, 2, 3), newEdge(1, 3, 2), newEdge(2, 3, 1), newEdge(3, 4, 5)}
+	if w := bruteForceMSTWeight(5, edges); w != 8 {
+		t.Errorf("bruteForceMSTWeight = %d, want 8", w)
+	}
+	if c := countComponents(5, edges); c != 2 {
+		t.Errorf("countComponents = %d, want 2", c)
+	}
+}
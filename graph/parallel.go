@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Split `items` into at most `workers` chunks and call `process` for every item, with one goroutine
+// per chunk. The outputs are concatenated in the order of `items`, so the result doesn't depend on
+// the scheduling of goroutines. `process` must only read shared state.
+func parallelMap[T any](items []int, workers int, process func(item int) []T) []T {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(items))
+	if workers <= 1 {
+		result := []T{}
+		for _, item := range items {
+			result = append(result, process(item)...)
+		}
+		return result
+	}
+
+	chunkSize := (len(items) + workers - 1) / workers
+	results := make([][]T, workers) // Each goroutine writes only to its own slot.
+	var wg sync.WaitGroup
+	for w := range workers {
+		start := w * chunkSize
+		end := min(start+chunkSize, len(items))
+		if start >= end {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, item := range items[start:end] {
+				results[w] = append(results[w], process(item)...)
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := []T{}
+	for _, chunk := range results {
+		result = append(result, chunk...)
+	}
+	return result
+}
+
+// Level-synchronous parallel breadth first search starting from a node `node`.
+// The frontier (all nodes at the same distance from `node`) is split between `workers` goroutines,
+// which gather the unvisited neighbors of their nodes. Then the neighbors are merged sequentially,
+// in the frontier order, into the next frontier. Thanks to that, the result is identical to BFS().
+// If workers < 1, the number of CPUs is used.
+func (g *Graph) ParallelBFS(node int, workers int) []int {
+	if node < 1 || node > g.Nodes {
+		panic(fmt.Sprintf("ParallelBFS: node should be in range [1, %v], got %v", g.Nodes, node))
+	}
+
+	// A slice instead of a Set, as goroutines read it concurrently. It's only written in the merge step.
+	seen := make([]bool, g.Nodes+1)
+	seen[node] = true
+	frontier := []int{node}
+	result := []int{}
+
+	for len(frontier) > 0 {
+		result = append(result, frontier...)
+
+		candidates := parallelMap(frontier, workers, func(v int) []int {
+			neighbors := []int{}
+			for _, edge := range g.AdjacencyList[v] {
+				if !seen[edge.To] {
+					neighbors = append(neighbors, edge.To)
+				}
+			}
+			return neighbors
+		})
+
+		// The same node might be a candidate of several frontier nodes, only the first one counts.
+		next := []int{}
+		for _, v := range candidates {
+			if !seen[v] {
+				seen[v] = true
+				next = append(next, v)
+			}
+		}
+		frontier = next
+	}
+	return result
+}
+
+// A request to update the distance to node `To` to `Dist`, via node `From`.
+type relaxRequest struct {
+	From int
+	To   int
+	Dist int
+}
+
+// Delta-stepping single source shortest paths, a parallel alternative to Dijkstra's algorithm.
+// Nodes are kept in buckets by their tentative distance, bucket i holds distances in [i*delta, (i+1)*delta).
+// Buckets are processed in order. Edges with weight <= delta (light edges) can put nodes back into
+// the current bucket, so they are relaxed repeatedly until the bucket stays empty. Heavy edges are
+// relaxed once per bucket afterwards. Relaxations of all nodes in a bucket are independent, so they
+// are computed by `workers` goroutines, and then applied sequentially.
+// With delta=1 this behaves like Dijkstra, with a very large delta like Bellman-Ford.
+//
+// Return the same maps as Dijkstra(). The predecessors are picked at the end, once the distances
+// are known, with the same rule as Dijkstra uses for ties, so they are identical too.
+// Panic if the graph has negative weights.
+// If workers < 1, the number of CPUs is used.
+func (g *Graph) DeltaStepping(source int, delta int, workers int) (map[int]int, map[int]int) {
+	if source < 1 || source > g.Nodes {
+		panic(fmt.Sprintf("DeltaStepping: source should be in range [1, %v], got %v", g.Nodes, source))
+	}
+	if delta < 1 {
+		panic(fmt.Sprintf("DeltaStepping: delta should be positive, got %v", delta))
+	}
+	for _, edges := range g.AdjacencyList {
+		for _, edge := range edges {
+			if edge.Weight < 0 {
+				panic("DeltaStepping: negative edge weights are not supported")
+			}
+		}
+	}
+
+	// Slices for distances, goroutines read them concurrently. Converted to maps at the end.
+	dist := make([]int, g.Nodes+1)
+	prev := make([]int, g.Nodes+1)
+	for i := range dist {
+		dist[i] = math.MaxInt
+		prev[i] = -1
+	}
+	dist[source] = 0
+	prev[source] = 0
+
+	// Only non-empty buckets are stored, so large distances with a small delta don't need a bucket
+	// for every index in between. The heap holds the indices of the stored buckets, to find the next one.
+	buckets := map[int][]int{0: {source}}
+	indices := NewHeap([]int{0}, []int{0})
+
+	// Apply the relaxation requests in order and put improved nodes into their new buckets.
+	// Nodes are not removed from their old buckets, such stale entries are skipped later.
+	apply := func(requests []relaxRequest) {
+		for _, r := range requests {
+			if r.Dist < dist[r.To] {
+				dist[r.To] = r.Dist
+				index := r.Dist / delta
+				if _, exists := buckets[index]; !exists {
+					indices.Push(index, index)
+				}
+				buckets[index] = append(buckets[index], r.To)
+			}
+		}
+	}
+
+	// Get a function that relaxes all outgoing edges of a node, which are light or heavy.
+	relax := func(light bool) func(v int) []relaxRequest {
+		return func(v int) []relaxRequest {
+			requests := []relaxRequest{}
+			for _, edge := range g.AdjacencyList[v] {
+				if (edge.Weight <= delta) == light && dist[v]+edge.Weight < dist[edge.To] {
+					requests = append(requests, relaxRequest{From: v, To: edge.To, Dist: dist[v] + edge.Weight})
+				}
+			}
+			return requests
+		}
+	}
+
+	for indices.Len() > 0 {
+		// New distances are never smaller than those in the current bucket, so the buckets are
+		// processed in increasing order, and the current one stays in the map until it's done.
+		i, _, _ := indices.PopMin()
+		settled := []int{} // All nodes removed from bucket i, their heavy edges are relaxed at the end.
+		inSettled := make(map[int]bool)
+		for len(buckets[i]) > 0 {
+			frontier := []int{}
+			inFrontier := make(map[int]bool)
+			for _, v := range buckets[i] {
+				if dist[v]/delta == i && !inFrontier[v] { // Skip stale and repeated entries.
+					inFrontier[v] = true
+					frontier = append(frontier, v)
+				}
+			}
+			buckets[i] = nil
+
+			for _, v := range frontier {
+				if !inSettled[v] {
+					inSettled[v] = true
+					settled = append(settled, v)
+				}
+			}
+			apply(parallelMap(frontier, workers, relax(true)))
+		}
+		apply(parallelMap(settled, workers, relax(false))) // Heavy edges only lead to later buckets.
+		delete(buckets, i)
+	}
+
+	// Dijkstra settles nodes in order of distance, then node number, and the first settled node
+	// on a shortest path to a node becomes its predecessor. Find the same one among the nodes
+	// whose edge lies on a shortest path, the distance of such node is passed in the request.
+	nodes := make([]int, g.Nodes)
+	for i := range nodes {
+		nodes[i] = i + 1
+	}
+	onShortestPath := parallelMap(nodes, workers, func(v int) []relaxRequest {
+		requests := []relaxRequest{}
+		if dist[v] == math.MaxInt {
+			return requests
+		}
+		for _, edge := range g.AdjacencyList[v] {
+			if dist[v]+edge.Weight == dist[edge.To] {
+				requests = append(requests, relaxRequest{From: v, To: edge.To, Dist: dist[v]})
+			}
+		}
+		return requests
+	})
+	for _, r := range onShortestPath {
+		if p := prev[r.To]; p == -1 || r.Dist < dist[p] || r.Dist == dist[p] && r.From < p {
+			prev[r.To] = r.From
+		}
+	}
+
+	distMap := make(map[int]int)
+	prevMap := make(map[int]int)
+	for node := 1; node <= g.Nodes; node++ {
+		distMap[node] = dist[node]
+		prevMap[node] = prev[node]
+	}
+	return distMap, prevMap
+}
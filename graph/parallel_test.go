@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// ParallelBFS should return exactly the same order as BFS, for any number of workers.
+func TestParallelBFS(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(30)+1, i%2 == 0, r.Float64()*0.3, 5)
+		for _, workers := range []int{0, 1, 2, 3, 8} {
+			for source := 1; source <= g.Nodes; source += 7 {
+				expected := g.BFS(source)
+				got := g.ParallelBFS(source, workers)
+				if !slicesEqual(got, expected) {
+					t.Fatalf("ParallelBFS(%d, %d) = %v, want %v", source, workers, got, expected)
+				}
+			}
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected ParallelBFS to panic on invalid node")
+		}
+	}()
+	g := NewEmptyGraph(true)
+	g.AddNodes(2)
+	g.ParallelBFS(3, 2)
+}
+
+// DeltaStepping should return the same distances and predecessors as Dijkstra. Small weights make
+// many nodes have several shortest paths.
+func TestDeltaStepping(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 100; i++ {
+		g := randomGraph(r, r.Intn(30)+1, i%2 == 0, r.Float64()*0.3, []int{3, 20}[i%2])
+		for _, delta := range []int{1, 3, 10, 1000} {
+			for _, workers := range []int{1, 4} {
+				source := r.Intn(g.Nodes) + 1
+				expectedDist, expectedPrev := g.Dijkstra(source)
+				dist, prev := g.DeltaStepping(source, delta, workers)
+				if !reflect.DeepEqual(dist, expectedDist) {
+					t.Fatalf("DeltaStepping(%d, %d, %d) = %v, want %v", source, delta, workers, dist, expectedDist)
+				}
+				if !reflect.DeepEqual(prev, expectedPrev) {
+					t.Fatalf("DeltaStepping(%d, %d, %d) predecessors = %v, want %v", source, delta, workers, prev, expectedPrev)
+				}
+			}
+		}
+	}
+}
+
+// Compare with the main Dijkstra example from dijkstra_test.go.
+func TestDeltaSteppingMainExample(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(8)
+	g.ConnectNodes(1, 2, 4)
+	g.ConnectNodes(1, 3, 2)
+	g.ConnectNodes(1, 6, 7)
+	g.ConnectNodes(2, 4, 2)
+	g.ConnectNodes(4, 7, 6)
+	g.ConnectNodes(4, 6, 5)
+	g.ConnectNodes(3, 6, 3)
+	g.ConnectNodes(3, 5, 8)
+	g.ConnectNodes(6, 8, 4)
+	g.ConnectNodes(5, 8, 3)
+	g.ConnectNodes(7, 8, 2)
+
+	dist, _ := g.DeltaStepping(1, 3, 4)
+	expected := map[int]int{1: 0, 2: 4, 3: 2, 4: 6, 5: 10, 6: 5, 7: 12, 8: 9}
+	if !reflect.DeepEqual(dist, expected) {
+		t.Errorf("DeltaStepping = %v, want %v", dist, expected)
+	}
+}
+
+// Large weights with a small delta leave most buckets empty, they must not be allocated or visited.
+func TestDeltaSteppingLargeWeights(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(2)
+	g.ConnectNodes(1, 2, 50_000_000)
+	dist, _ := g.DeltaStepping(1, 1, 4)
+	if dist[2] != 50_000_000 {
+		t.Errorf("DeltaStepping distance to 2 = %d, want 50000000", dist[2])
+	}
+
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		g := randomGraph(r, r.Intn(30)+1, i%2 == 0, 0.2, 1_000_000_000)
+		expected, _ := g.Dijkstra(1)
+		if dist, _ := g.DeltaStepping(1, 1, 4); !reflect.DeepEqual(dist, expected) {
+			t.Fatalf("DeltaStepping(1, 1, 4) = %v, want %v", dist, expected)
+		}
+	}
+}
+
+func TestDeltaSteppingPanics(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(2)
+	g.ConnectNodes(1, 2, -1)
+
+	tests := []struct {
+		name   string
+		source int
+		delta  int
+	}{
+		{"Invalid source", 3, 1},
+		{"Invalid delta", 1, 0},
+		{"Negative weight", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected DeltaStepping to panic")
+				}
+			}()
+			g.DeltaStepping(tt.source, tt.delta, 1)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summary of the structure of a graph, see Graph.Stats.
+type GraphStats struct {
+	Nodes    int
+	Edges    int // Undirected edges are counted once.
+	Directed bool
+	Density  float64 // Edges divided by the maximum possible number of edges without self-loops.
+
+	// Degrees count all neighbors, for directed graphs it's the in-degree plus the out-degree.
+	MinDegree          int
+	MaxDegree          int
+	AverageDegree      float64
+	DegreeDistribution map[int]int // Number of nodes with each degree.
+
+	Components  int  // Connected components, ignoring the direction of edges.
+	IsDAG       bool // Directed and without cycles, always false for undirected graphs.
+	IsTree      bool // Connected and without cycles. A directed tree must have all edges pointing away from the root.
+	IsBipartite bool // Nodes can be split into two sets with all edges between them, ignoring directions.
+
+	// Average local clustering coefficient, ignoring directions. The local coefficient of a node is
+	// the fraction of pairs of its neighbors that are connected, 0 for nodes with less than 2 neighbors.
+	ClusteringCoefficient float64
+
+	// Inconsistencies found in the AdjacencyList, eg. an undirected edge stored in one direction only.
+	// Empty for graphs built with ConnectNodes. Other statistics only use the valid edges.
+	Problems []string
+}
+
+// Collect statistics about the graph, for debugging and monitoring. The graph is also checked for
+// inconsistencies that ConnectNodes would not allow, in case the AdjacencyList was modified directly.
+func (g *Graph) Stats() GraphStats {
+	stats := GraphStats{Nodes: g.Nodes, Directed: g.Directed, DegreeDistribution: make(map[int]int)}
+
+	// Valid edges, checked against everything ConnectNodes guarantees.
+	out := make([]map[int]int, g.Nodes+1) // out[from][to] is the weight.
+	for node := 1; node <= g.Nodes; node++ {
+		out[node] = make(map[int]int)
+	}
+	keys := []int{}
+	for key := range g.AdjacencyList {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys) // So that the problems are reported in a stable order.
+	for _, key := range keys {
+		if key < 1 || key > g.Nodes {
+			stats.Problems = append(stats.Problems, fmt.Sprintf("adjacency list of node %v, which is out of range [1, %v]", key, g.Nodes))
+			continue
+		}
+		for _, edge := range g.AdjacencyList[key] {
+			switch {
+			case edge.From != key:
+				stats.Problems = append(stats.Problems, fmt.Sprintf("edge %v->%v is stored in the list of node %v", edge.From, edge.To, key))
+			case edge.To < 1 || edge.To > g.Nodes:
+				stats.Problems = append(stats.Problems, fmt.Sprintf("edge %v->%v points to a node out of range [1, %v]", edge.From, edge.To, g.Nodes))
+			case edge.From == edge.To:
+				stats.Problems = append(stats.Problems, fmt.Sprintf("self-loop on node %v", key))
+			case edge.Weight == 0:
+				stats.Problems = append(stats.Problems, fmt.Sprintf("edge %v->%v has weight 0", edge.From, edge.To))
+			default:
+				if _, exists := out[key][edge.To]; exists {
+					stats.Problems = append(stats.Problems, fmt.Sprintf("duplicate edge %v->%v", edge.From, edge.To))
+				} else {
+					out[key][edge.To] = edge.Weight
+				}
+			}
+		}
+	}
+	if !g.Directed {
+		for from := 1; from <= g.Nodes; from++ {
+			targets := []int{}
+			for to := range out[from] {
+				targets = append(targets, to)
+			}
+			sort.Ints(targets)
+			for _, to := range targets {
+				weight := out[from][to]
+				reverse, exists := out[to][from]
+				if !exists {
+					stats.Problems = append(stats.Problems, fmt.Sprintf("undirected edge %v-%v is only stored as %v->%v", from, to, from, to))
+				} else if reverse != weight && from < to {
+					stats.Problems = append(stats.Problems, fmt.Sprintf("undirected edge %v-%v has weights %v and %v", from, to, weight, reverse))
+				}
+			}
+		}
+	}
+
+	// From here on, directions are ignored unless needed.
+	neighbors := make([]map[int]bool, g.Nodes+1)
+	for node := 1; node <= g.Nodes; node++ {
+		neighbors[node] = make(map[int]bool)
+	}
+	for from := 1; from <= g.Nodes; from++ {
+		for to := range out[from] {
+			neighbors[from][to] = true
+			neighbors[to][from] = true
+			stats.Edges++
+		}
+	}
+	if !g.Directed {
+		// Count every pair of neighbors once, even if an edge is only stored in one direction.
+		stats.Edges = 0
+		for node := 1; node <= g.Nodes; node++ {
+			stats.Edges += len(neighbors[node])
+		}
+		stats.Edges /= 2
+	}
+
+	if g.Nodes > 1 {
+		possible := g.Nodes * (g.Nodes - 1)
+		if !g.Directed {
+			possible /= 2
+		}
+		stats.Density = float64(stats.Edges) / float64(possible)
+	}
+
+	inDegree := make([]int, g.Nodes+1)
+	for from := 1; from <= g.Nodes; from++ {
+		for to := range out[from] {
+			inDegree[to]++
+		}
+	}
+	totalDegree := 0
+	for node := 1; node <= g.Nodes; node++ {
+		degree := len(neighbors[node])
+		if g.Directed {
+			degree = len(out[node]) + inDegree[node]
+		}
+		stats.DegreeDistribution[degree]++
+		totalDegree += degree
+		if node == 1 || degree < stats.MinDegree {
+			stats.MinDegree = degree
+		}
+		stats.MaxDegree = max(stats.MaxDegree, degree)
+	}
+	if g.Nodes > 0 {
+		stats.AverageDegree = float64(totalDegree) / float64(g.Nodes)
+	}
+
+	// Components and bipartiteness with a BFS that 2-colors the nodes.
+	side := make([]int, g.Nodes+1) // 0 means not visited yet, otherwise 1 or 2.
+	stats.IsBipartite = true
+	for start := 1; start <= g.Nodes; start++ {
+		if side[start] != 0 {
+			continue
+		}
+		stats.Components++
+		side[start] = 1
+		queue := NewQueue()
+		queue.Enqueue(start)
+		for queue.Length() > 0 {
+			v := queue.Dequeue()
+			for u := range neighbors[v] {
+				if side[u] == 0 {
+					side[u] = 3 - side[v]
+					queue.Enqueue(u)
+				} else if side[u] == side[v] {
+					stats.IsBipartite = false
+				}
+			}
+		}
+	}
+
+	if g.Directed {
+		// Kahn's algorithm, the graph is a DAG if all nodes can be removed.
+		remaining := append([]int{}, inDegree...)
+		queue := NewQueue()
+		for node := 1; node <= g.Nodes; node++ {
+			if remaining[node] == 0 {
+				queue.Enqueue(node)
+			}
+		}
+		removed := 0
+		for queue.Length() > 0 {
+			v := queue.Dequeue()
+			removed++
+			for to := range out[v] {
+				remaining[to]--
+				if remaining[to] == 0 {
+					queue.Enqueue(to)
+				}
+			}
+		}
+		stats.IsDAG = removed == g.Nodes
+	}
+	// A connected graph with n-1 edges has no cycles. A directed one also needs in-degrees of at most 1,
+	// so that all edges point away from the root.
+	stats.IsTree = g.Nodes > 0 && stats.Components == 1 && stats.Edges == g.Nodes-1
+	if g.Directed {
+		for node := 1; node <= g.Nodes; node++ {
+			stats.IsTree = stats.IsTree && inDegree[node] <= 1
+		}
+	}
+
+	total := 0.0
+	for node := 1; node <= g.Nodes; node++ {
+		degree := len(neighbors[node])
+		if degree < 2 {
+			continue
+		}
+		links := 0
+		for a := range neighbors[node] {
+			for b := range neighbors[node] {
+				if a < b && neighbors[a][b] {
+					links++
+				}
+			}
+		}
+		total += float64(links) / float64(degree*(degree-1)/2)
+	}
+	if g.Nodes > 0 {
+		stats.ClusteringCoefficient = total / float64(g.Nodes)
+	}
+	return stats
+}
+
+// Get a human-readable, multi-line report of the statistics.
+func (s GraphStats) String() string {
+	var b strings.Builder
+	kind := "undirected"
+	if s.Directed {
+		kind = "directed"
+	}
+	fmt.Fprintf(&b, "Graph: %v, %v nodes, %v edges\n", kind, s.Nodes, s.Edges)
+	fmt.Fprintf(&b, "Density: %.4f\n", s.Density)
+	fmt.Fprintf(&b, "Degree: min %v, max %v, average %.2f\n", s.MinDegree, s.MaxDegree, s.AverageDegree)
+
+	degrees := []int{}
+	for degree := range s.DegreeDistribution {
+		degrees = append(degrees, degree)
+	}
+	sort.Ints(degrees)
+	distribution := []string{}
+	for _, degree := range degrees {
+		distribution = append(distribution, fmt.Sprintf("%v:%v", degree, s.DegreeDistribution[degree]))
+	}
+	fmt.Fprintf(&b, "Degree distribution: %v\n", strings.Join(distribution, " "))
+
+	fmt.Fprintf(&b, "Components: %v\n", s.Components)
+	fmt.Fprintf(&b, "DAG: %v, tree: %v, bipartite: %v\n", s.IsDAG, s.IsTree, s.IsBipartite)
+	fmt.Fprintf(&b, "Clustering coefficient: %.4f\n", s.ClusteringCoefficient)
+	if len(s.Problems) == 0 {
+		b.WriteString("Consistency: OK")
+	} else {
+		fmt.Fprintf(&b, "Consistency: %v problems", len(s.Problems))
+		for _, problem := range s.Problems {
+			fmt.Fprintf(&b, "\n  - %v", problem)
+		}
+	}
+	return b.String()
+}
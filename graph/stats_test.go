@@ -0,0 +1,131 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	// A triangle 1-2-3 with a pendant node 4 attached to 3, and an isolated node 5.
+	g := NewEmptyGraph(false)
+	g.AddNodes(5)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 3, 1)
+	g.ConnectNodes(3, 1, 1)
+	g.ConnectNodes(3, 4, 1)
+
+	stats := g.Stats()
+	if stats.Nodes != 5 || stats.Edges != 4 || stats.Components != 2 {
+		t.Errorf("Expected 5 nodes, 4 edges and 2 components, got %+v", stats)
+	}
+	if stats.IsDAG || stats.IsTree || stats.IsBipartite {
+		t.Errorf("Expected a graph that is not a DAG, a tree or bipartite, got %+v", stats)
+	}
+	if !reflect.DeepEqual(stats.DegreeDistribution, map[int]int{0: 1, 1: 1, 2: 2, 3: 1}) {
+		t.Errorf("Unexpected degree distribution %v", stats.DegreeDistribution)
+	}
+	// Nodes 1 and 2 have coefficient 1, node 3 has 1 link out of 3 pairs of neighbors.
+	if math.Abs(stats.ClusteringCoefficient-(1+1+1.0/3)/5) > 1e-9 {
+		t.Errorf("Unexpected clustering coefficient %v", stats.ClusteringCoefficient)
+	}
+
+	expected := `Graph: undirected, 5 nodes, 4 edges
+Density: 0.4000
+Degree: min 0, max 3, average 1.60
+Degree distribution: 0:1 1:1 2:2 3:1
+Components: 2
+DAG: false, tree: false, bipartite: false
+Clustering coefficient: 0.4667
+Consistency: OK`
+	if stats.String() != expected {
+		t.Errorf("Expected report:\n%v\ngot:\n%v", expected, stats.String())
+	}
+
+	// Directed out-tree: 1 -> 2, 1 -> 3, 3 -> 4. With two edges into the same node it's only a DAG.
+	d := NewEmptyGraph(true)
+	d.AddNodes(4)
+	d.ConnectNodes(1, 2, 1)
+	d.ConnectNodes(1, 3, 1)
+	d.ConnectNodes(3, 4, 1)
+	if stats := d.Stats(); !stats.IsDAG || !stats.IsTree || !stats.IsBipartite || stats.Edges != 3 {
+		t.Errorf("Expected a directed tree, got %+v", stats)
+	}
+	d = NewEmptyGraph(true)
+	d.AddNodes(4)
+	d.ConnectNodes(1, 2, 1)
+	d.ConnectNodes(3, 2, 1)
+	d.ConnectNodes(3, 4, 1)
+	if stats := d.Stats(); !stats.IsDAG || stats.IsTree {
+		t.Errorf("Expected a DAG that is not a tree, got %+v", stats)
+	}
+
+	empty := NewEmptyGraph(false)
+	if stats := empty.Stats(); stats.Nodes != 0 || stats.Components != 0 || stats.Density != 0 {
+		t.Errorf("Expected empty statistics, got %+v", stats)
+	}
+}
+
+func TestStatsProblems(t *testing.T) {
+	g := NewEmptyGraph(false)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 5)
+	g.ConnectNodes(2, 3, 1)
+	// Break the graph the way a direct modification of the AdjacencyList could.
+	g.AdjacencyList[1] = append(g.AdjacencyList[1], Edge{From: 1, To: 3, Weight: 1})
+	g.AdjacencyList[2][0].Weight = 4
+	g.AdjacencyList[3] = append(g.AdjacencyList[3], Edge{From: 3, To: 3, Weight: 1}, Edge{From: 3, To: 7, Weight: 1})
+
+	expected := []string{
+		"self-loop on node 3",
+		"edge 3->7 points to a node out of range [1, 3]",
+		"undirected edge 1-2 has weights 5 and 4",
+		"undirected edge 1-3 is only stored as 1->3",
+	}
+	stats := g.Stats()
+	if !reflect.DeepEqual(stats.Problems, expected) {
+		t.Errorf("Expected problems %q, got %q", expected, stats.Problems)
+	}
+	// The one-directional edge is still counted.
+	if stats.Edges != 3 || stats.IsBipartite {
+		t.Errorf("Expected 3 edges forming a triangle, got %+v", stats)
+	}
+}
+
+// Compare the flags with the algorithms that compute them separately.
+func TestStatsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(40))
+	for i := 0; i < 200; i++ {
+		directed := i%2 == 0
+		g := randomGraph(r, r.Intn(8)+1, directed, r.Float64()*0.5, 5)
+		stats := g.Stats()
+
+		if len(stats.Problems) != 0 {
+			t.Fatalf("Expected no problems, got %v", stats.Problems)
+		}
+		if components := countComponents(g.Nodes, edgeList(&g)); stats.Components != components {
+			t.Fatalf("Expected %d components, got %d", components, stats.Components)
+		}
+		if directed {
+			_, err := g.KahnTopoSort()
+			if stats.IsDAG != (err == nil) {
+				t.Fatalf("IsDAG = %v, but KahnTopoSort returned %v", stats.IsDAG, err)
+			}
+			continue
+		}
+		if _, err := g.RootedTree(1); stats.IsTree != (err == nil) {
+			t.Fatalf("IsTree = %v, but RootedTree returned %v", stats.IsTree, err)
+		}
+		if _, k := g.ExactColoring(); stats.IsBipartite != (k <= 2) {
+			t.Fatalf("IsBipartite = %v, but the chromatic number is %d", stats.IsBipartite, k)
+		}
+		sum := 0
+		for degree, count := range stats.DegreeDistribution {
+			sum += degree * count
+		}
+		if sum != 2*stats.Edges {
+			t.Fatalf("Degrees sum to %d, expected twice the %d edges", sum, stats.Edges)
+		}
+	}
+}
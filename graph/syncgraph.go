@@ -0,0 +1,99 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Thread-safe wrapper around Graph. Mutations are protected by a RWMutex, reads go through
+// snapshots. A snapshot is a *Graph that never changes, so long-running algorithms such as
+// Dijkstra or BFS can run on it without holding any lock and see a consistent view, while
+// other goroutines keep modifying the SyncGraph.
+//
+// Mutations change the graph in place and never copy it, they only mark the last snapshot as out
+// of date. The last snapshot is published through an atomic pointer, so taking a snapshot when
+// nothing changed since the previous one is O(1) and doesn't take any lock. The first snapshot after
+// a mutation copies the adjacency list map (but not the edges), which is O(V), under the read lock,
+// so readers don't block each other. If the graph changes between every two snapshots, every
+// snapshot pays that copy, and it's cheaper to batch the mutations.
+type SyncGraph struct {
+	mu       sync.RWMutex
+	graph    *Graph                // The current graph, only used with `mu` held.
+	snapshot atomic.Pointer[Graph] // The last snapshot, nil if the graph changed since it was taken.
+}
+
+// Get a new empty thread-safe graph.
+func NewSyncGraph(directed bool) *SyncGraph {
+	g := NewEmptyGraph(directed)
+	return &SyncGraph{graph: &g}
+}
+
+// Get a new thread-safe graph, initialized with a clone of `g`.
+func NewSyncGraphFrom(g *Graph) *SyncGraph {
+	clone := g.Clone()
+	return &SyncGraph{graph: &clone}
+}
+
+// Add numNodes number of nodes to the graph. Panic if numNodes less than one.
+func (s *SyncGraph) AddNodes(numNodes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.graph.AddNodes(numNodes)
+	s.snapshot.Store(nil) // Still under the lock, so no snapshot of the old graph can be taken after this.
+}
+
+// Connect node `from` with node `to`, same as Graph.ConnectNodes.
+func (s *SyncGraph) ConnectNodes(from int, to int, weight int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.graph.ConnectNodes(from, to, weight)
+	s.snapshot.Store(nil)
+}
+
+// Get a consistent, read-only view of the graph. The returned graph is shared with other snapshots,
+// it must not be modified. Use Clone() on it to get a modifiable copy.
+func (s *SyncGraph) Snapshot() *Graph {
+	if snapshot := s.snapshot.Load(); snapshot != nil {
+		return snapshot
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if snapshot := s.snapshot.Load(); snapshot != nil {
+		return snapshot // Another reader took it first.
+	}
+	// The edge slices are shared with the current graph, but their capacity is capped at their length.
+	// ConnectNodes only appends to them, after the end of the shared part.
+	adjacencyList := make(map[int][]Edge, len(s.graph.AdjacencyList))
+	for node, edges := range s.graph.AdjacencyList {
+		adjacencyList[node] = edges[:len(edges):len(edges)]
+	}
+	snapshot := &Graph{Nodes: s.graph.Nodes, AdjacencyList: adjacencyList, Directed: s.graph.Directed}
+	if !s.snapshot.CompareAndSwap(nil, snapshot) {
+		return s.snapshot.Load() // Another reader published an identical one in the meantime.
+	}
+	return snapshot
+}
+
+// Get the current number of nodes.
+func (s *SyncGraph) Nodes() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.graph.Nodes
+}
+
+// Check if there is an edge between nodes `from` and `to`.
+func (s *SyncGraph) EdgeExists(from int, to int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.graph.edgeExists(from, to)
+}
+
+// Run Dijkstra's algorithm on a snapshot of the graph.
+func (s *SyncGraph) Dijkstra(source int) (map[int]int, map[int]int) {
+	return s.Snapshot().Dijkstra(source)
+}
+
+// Run breadth first search on a snapshot of the graph.
+func (s *SyncGraph) BFS(node int) []int {
+	return s.Snapshot().BFS(node)
+}
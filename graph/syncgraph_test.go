@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Snapshots should not change when the SyncGraph is modified afterwards.
+func TestSyncGraphSnapshot(t *testing.T) {
+	s := NewSyncGraph(true)
+	s.AddNodes(3)
+	s.ConnectNodes(1, 2, 1)
+
+	snap := s.Snapshot()
+	before := snap.Clone()
+
+	s.ConnectNodes(1, 3, 1) // Appends to the adjacency list of node 1, which the snapshot shares.
+	s.ConnectNodes(2, 3, 1)
+	s.AddNodes(2)
+
+	if !reflect.DeepEqual(*snap, before) {
+		t.Errorf("Snapshot changed after mutations: got %v, want %v", *snap, before)
+	}
+	if s.Nodes() != 5 || !s.EdgeExists(1, 3) || !s.EdgeExists(2, 3) {
+		t.Errorf("Mutations were not applied to the SyncGraph")
+	}
+
+	// Taking two snapshots without mutations in between gives the same graph.
+	if s.Snapshot() != s.Snapshot() {
+		t.Errorf("Expected consecutive snapshots to share the graph")
+	}
+}
+
+// A snapshot taken when nothing changed is returned without taking any lock, even while a writer
+// holds it, and mutations don't copy the graph the SyncGraph works on.
+func TestSyncGraphSnapshotWithoutLock(t *testing.T) {
+	s := NewSyncGraph(true)
+	s.AddNodes(2)
+	snap := s.Snapshot()
+
+	s.mu.Lock()
+	if s.Snapshot() != snap {
+		t.Errorf("Expected the last snapshot while the write lock is held")
+	}
+	s.mu.Unlock()
+
+	graph := s.graph
+	s.ConnectNodes(1, 2, 1)
+	if s.graph != graph {
+		t.Errorf("ConnectNodes copied the graph")
+	}
+	if next := s.Snapshot(); next == snap || !next.edgeExists(1, 2) || snap.edgeExists(1, 2) {
+		t.Errorf("Expected a new snapshot with the edge, and the old one without it")
+	}
+}
+
+func TestNewSyncGraphFrom(t *testing.T) {
+	g := NewEmptyGraph(false)
+	g.AddNodes(2)
+	s := NewSyncGraphFrom(&g)
+	s.ConnectNodes(1, 2, 3)
+
+	if g.edgeExists(1, 2) {
+		t.Errorf("Modifying the SyncGraph changed the original graph")
+	}
+	if dist, _ := s.Dijkstra(2); dist[1] != 3 {
+		t.Errorf("Dijkstra on SyncGraph: expected distance 3, got %d", dist[1])
+	}
+}
+
+// One writer builds a path 1->2->...->n, while many readers run algorithms on snapshots.
+// Every snapshot must be a consistent prefix of the path, and must not change while it's used.
+// Run with `go test -race` to check for data races.
+func TestSyncGraphConcurrent(t *testing.T) {
+	const n = 200
+	s := NewSyncGraph(true)
+	s.AddNodes(1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for node := 2; node <= n; node++ {
+			s.AddNodes(1)
+			s.ConnectNodes(node-1, node, 1)
+		}
+	}()
+
+	errors := make(chan string, 100)
+	for reader := 0; reader < 8; reader++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				snap := s.Snapshot()
+				edges := len(edgeList(snap))
+
+				order := snap.BFS(1)
+				dist, _ := snap.Dijkstra(1)
+
+				// The last node might not be connected yet, as AddNodes and ConnectNodes are separate calls.
+				if len(order) != edges+1 || snap.Nodes-edges > 1 {
+					errors <- "inconsistent snapshot"
+					return
+				}
+				for j, node := range order {
+					if node != j+1 || dist[node] != j {
+						errors <- "unexpected BFS or Dijkstra result on a snapshot"
+						return
+					}
+				}
+				if len(edgeList(snap)) != edges {
+					errors <- "snapshot changed while in use"
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errors)
+
+	for err := range errors {
+		t.Error(err)
+	}
+	if order := s.BFS(1); len(order) != n {
+		t.Errorf("Expected BFS to visit %d nodes, got %d", n, len(order))
+	}
+}
@@ -44,12 +44,15 @@ func remove(s []Edge, e Edge) []Edge {
 }
 
 // Topological ordering of a graph using Kahn's algorithm. Only possible for directed graphs.
-// This function works with a copy of the original graph, as it removes edges during the procedure.
+// This function works with a clone of the original graph, as it removes edges during the procedure.
+// A plain copy of the Graph struct wouldn't be enough, it would share the adjacency list map.
 // If it's not possible to topologically sort a graph, because it has cycles, return an error.
-func (g Graph) KahnTopoSort() ([]int, error) {
+func (g *Graph) KahnTopoSort() ([]int, error) {
 	if !g.Directed {
 		panic("KahnTopoSort: cannot be applied to undirected graphs.")
 	}
+	clone := g.Clone()
+	g = &clone
 
 	result := []int{}
 	nodesToProcess := NewQueue()
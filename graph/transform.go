@@ -0,0 +1,160 @@
+package main
+
+import "fmt"
+
+// Get a deep copy of the graph. The adjacency list of the copy doesn't share any slices
+// with the original, so both graphs can be modified independently.
+func (g *Graph) Clone() Graph {
+	clone := NewEmptyGraph(g.Directed)
+	if g.Nodes > 0 {
+		clone.AddNodes(g.Nodes)
+	}
+	for node := 1; node <= g.Nodes; node++ {
+		clone.AdjacencyList[node] = append(clone.AdjacencyList[node], g.AdjacencyList[node]...)
+	}
+	return clone
+}
+
+// Get the transpose of a directed graph, ie. the same graph with every edge reversed.
+func (g *Graph) Transpose() Graph {
+	if !g.Directed {
+		panic("Transpose: cannot be applied to undirected graphs.")
+	}
+
+	transposed := NewEmptyGraph(true)
+	if g.Nodes > 0 {
+		transposed.AddNodes(g.Nodes)
+	}
+	for node := 1; node <= g.Nodes; node++ {
+		for _, edge := range g.AdjacencyList[node] {
+			transposed.AdjacencyList[edge.To] = append(transposed.AdjacencyList[edge.To], newEdge(edge.To, edge.From, edge.Weight))
+		}
+	}
+	return transposed
+}
+
+// Get the subgraph induced by `nodes`, ie. these nodes and all the edges between them.
+// The nodes are renumbered 1..len(nodes) in the order they were given. Return the subgraph
+// and a map from its nodes to the original ones. Panic on out of range or repeated nodes.
+func (g *Graph) InducedSubgraph(nodes []int) (Graph, map[int]int) {
+	toNew := make(map[int]int)      // Original node -> node in the subgraph.
+	toOriginal := make(map[int]int) // Node in the subgraph -> original node.
+	for i, node := range nodes {
+		if node < 1 || node > g.Nodes {
+			panic(fmt.Sprintf("InducedSubgraph: nodes should be in range [1, %v], got %v", g.Nodes, node))
+		}
+		if _, exists := toNew[node]; exists {
+			panic(fmt.Sprintf("InducedSubgraph: node %v is repeated", node))
+		}
+		toNew[node] = i + 1
+		toOriginal[i+1] = node
+	}
+
+	sub := NewEmptyGraph(g.Directed)
+	if len(nodes) > 0 {
+		sub.AddNodes(len(nodes))
+	}
+	// For undirected graphs both directions of an edge are in the adjacency list,
+	// so copying every entry keeps the subgraph symmetric.
+	for _, node := range nodes {
+		for _, edge := range g.AdjacencyList[node] {
+			if to, ok := toNew[edge.To]; ok {
+				from := toNew[node]
+				sub.AdjacencyList[from] = append(sub.AdjacencyList[from], newEdge(from, to, edge.Weight))
+			}
+		}
+	}
+	return sub, toOriginal
+}
+
+// Get the complement of the graph: the same nodes, connected exactly where the original
+// graph has no edge. The original weights are lost, all the new edges have weight 1.
+func (g *Graph) Complement() Graph {
+	complement := NewEmptyGraph(g.Directed)
+	if g.Nodes > 0 {
+		complement.AddNodes(g.Nodes)
+	}
+	for from := 1; from <= g.Nodes; from++ {
+		for to := 1; to <= g.Nodes; to++ {
+			if from != to && !g.edgeExists(from, to) {
+				complement.AdjacencyList[from] = append(complement.AdjacencyList[from], newEdge(from, to, 1))
+			}
+		}
+	}
+	return complement
+}
+
+// Get the disjoint union of two graphs. Nodes of `g` keep their numbers, nodes of `other` are
+// renumbered to g.Nodes+1..g.Nodes+other.Nodes. Return the union and a map from its nodes to
+// the original ones (in `g` for nodes up to g.Nodes, in `other` for the rest).
+// Panic if one graph is directed and the other one is not.
+func (g *Graph) Union(other *Graph) (Graph, map[int]int) {
+	if g.Directed != other.Directed {
+		panic("Union: cannot make a union of a directed and an undirected graph.")
+	}
+
+	union := g.Clone()
+	toOriginal := make(map[int]int)
+	for node := 1; node <= g.Nodes; node++ {
+		toOriginal[node] = node
+	}
+	if other.Nodes == 0 {
+		return union, toOriginal
+	}
+
+	offset := g.Nodes
+	union.AddNodes(other.Nodes)
+	for node := 1; node <= other.Nodes; node++ {
+		toOriginal[node+offset] = node
+		for _, edge := range other.AdjacencyList[node] {
+			union.AdjacencyList[node+offset] = append(union.AdjacencyList[node+offset], newEdge(node+offset, edge.To+offset, edge.Weight))
+		}
+	}
+	return union, toOriginal
+}
+
+// Get a directed version of the graph, where every undirected edge becomes two directed edges
+// with the same weight. For a directed graph return its clone.
+func (g *Graph) ToDirected() Graph {
+	directed := g.Clone() // The adjacency list of undirected graphs already stores both directions.
+	directed.Directed = true
+	return directed
+}
+
+// Get an undirected version of the graph, where nodes are connected if there's an edge between
+// them in any direction. If there are edges in both directions, the smaller weight is kept.
+// For an undirected graph return its clone.
+func (g *Graph) ToUndirected() Graph {
+	if !g.Directed {
+		return g.Clone()
+	}
+
+	undirected := NewEmptyGraph(false)
+	if g.Nodes > 0 {
+		undirected.AddNodes(g.Nodes)
+	}
+	for from := 1; from <= g.Nodes; from++ {
+		for _, edge := range g.AdjacencyList[from] {
+			weight := edge.Weight
+			// Handle each pair once, from the direction where it's seen first.
+			if reverse, exists := g.edgeWeight(edge.To, from); exists {
+				if edge.To < from {
+					continue
+				}
+				weight = min(weight, reverse)
+			}
+			undirected.ConnectNodes(from, edge.To, weight)
+		}
+	}
+	return undirected
+}
+
+// Get the weight of the edge between nodes `from` and `to`, and whether such edge exists.
+func (g *Graph) edgeWeight(from int, to int) (int, bool) {
+	for _, edge := range g.AdjacencyList[from] {
+		if edge.To == to {
+			return edge.Weight, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test that the clone is equal to the original, but independent of it.
+func TestClone(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 4)
+	g.ConnectNodes(2, 3, 5)
+
+	clone := g.Clone()
+	if !reflect.DeepEqual(clone, g) {
+		t.Fatalf("Clone = %v, want %v", clone, g)
+	}
+
+	clone.ConnectNodes(1, 3, 7)
+	clone.AddNodes(1)
+	if g.edgeExists(1, 3) || g.Nodes != 3 {
+		t.Errorf("Modifying the clone changed the original graph: %v", g)
+	}
+
+	empty := NewEmptyGraph(false)
+	if clone := empty.Clone(); clone.Nodes != 0 || clone.Directed {
+		t.Errorf("Clone of an empty graph = %v", clone)
+	}
+}
+
+// KahnTopoSort used to remove edges from the original graph, as its value receiver
+// shared the adjacency list.
+func TestKahnTopoSortKeepsGraph(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 3, 1)
+	before := g.Clone()
+
+	if _, err := g.KahnTopoSort(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(g, before) {
+		t.Errorf("KahnTopoSort modified the graph: got %v, want %v", g, before)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 4)
+	g.ConnectNodes(2, 3, 5)
+
+	tr := g.Transpose()
+	if !tr.edgeExists(2, 1) || !tr.edgeExists(3, 2) || tr.edgeExists(1, 2) || tr.edgeExists(2, 3) {
+		t.Errorf("Transpose: unexpected edges %v", tr.AdjacencyList)
+	}
+	if w, _ := tr.edgeWeight(3, 2); w != 5 {
+		t.Errorf("Transpose: expected weight 5 for edge 3->2, got %d", w)
+	}
+
+	// Transposing twice gives the original graph back.
+	back := tr.Transpose()
+	if !reflect.DeepEqual(back, g) {
+		t.Errorf("Transpose twice = %v, want %v", back, g)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected Transpose to panic on undirected graph")
+		}
+	}()
+	undirected := NewEmptyGraph(false)
+	undirected.Transpose()
+}
+
+func TestInducedSubgraph(t *testing.T) {
+	g := NewEmptyGraph(false)
+	g.AddNodes(5)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(2, 4, 2)
+	g.ConnectNodes(4, 5, 3)
+	g.ConnectNodes(1, 4, 4)
+
+	sub, mapping := g.InducedSubgraph([]int{4, 1, 5})
+	if sub.Nodes != 3 || sub.Directed {
+		t.Fatalf("InducedSubgraph: got %d nodes, directed=%v", sub.Nodes, sub.Directed)
+	}
+	expectedMapping := map[int]int{1: 4, 2: 1, 3: 5}
+	if !reflect.DeepEqual(mapping, expectedMapping) {
+		t.Errorf("InducedSubgraph mapping = %v, want %v", mapping, expectedMapping)
+	}
+
+	// Every edge of the subgraph exists in the original one, and vice versa for the chosen nodes.
+	for from := 1; from <= sub.Nodes; from++ {
+		for to := 1; to <= sub.Nodes; to++ {
+			subWeight, inSub := sub.edgeWeight(from, to)
+			weight, inOriginal := g.edgeWeight(mapping[from], mapping[to])
+			if inSub != inOriginal || subWeight != weight {
+				t.Errorf("InducedSubgraph: edge %d-%d mismatch with original %d-%d", from, to, mapping[from], mapping[to])
+			}
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected InducedSubgraph to panic on repeated nodes")
+		}
+	}()
+	g.InducedSubgraph([]int{1, 1})
+}
+
+func TestComplement(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 4)
+	g.ConnectNodes(2, 3, 5)
+
+	c := g.Complement()
+	for from := 1; from <= 3; from++ {
+		for to := 1; to <= 3; to++ {
+			if from == to {
+				continue
+			}
+			if c.edgeExists(from, to) == g.edgeExists(from, to) {
+				t.Errorf("Complement: edge %d->%d should exist in exactly one of the graphs", from, to)
+			}
+		}
+	}
+
+	// Complement of an undirected graph stays symmetric.
+	u := NewEmptyGraph(false)
+	u.AddNodes(4)
+	u.ConnectNodes(1, 2, 1)
+	uc := u.Complement()
+	if uc.edgeExists(1, 2) || !uc.edgeExists(3, 4) || !uc.edgeExists(4, 3) || len(edgeList(&uc)) != 5 {
+		t.Errorf("Complement: unexpected edges %v", uc.AdjacencyList)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	a := NewEmptyGraph(true)
+	a.AddNodes(2)
+	a.ConnectNodes(1, 2, 3)
+
+	b := NewEmptyGraph(true)
+	b.AddNodes(3)
+	b.ConnectNodes(3, 1, 7)
+
+	u, mapping := a.Union(&b)
+	if u.Nodes != 5 {
+		t.Fatalf("Union: got %d nodes, want 5", u.Nodes)
+	}
+	if !u.edgeExists(1, 2) || !u.edgeExists(5, 3) || len(edgeList(&u)) != 2 {
+		t.Errorf("Union: unexpected edges %v", u.AdjacencyList)
+	}
+	expectedMapping := map[int]int{1: 1, 2: 2, 3: 1, 4: 2, 5: 3}
+	if !reflect.DeepEqual(mapping, expectedMapping) {
+		t.Errorf("Union mapping = %v, want %v", mapping, expectedMapping)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected Union to panic for mixed directed and undirected graphs")
+		}
+	}()
+	undirected := NewEmptyGraph(false)
+	a.Union(&undirected)
+}
+
+func TestDirectedUndirectedConversion(t *testing.T) {
+	g := NewEmptyGraph(true)
+	g.AddNodes(3)
+	g.ConnectNodes(1, 2, 4)
+	g.ConnectNodes(2, 1, 2)
+	g.ConnectNodes(2, 3, 5)
+
+	u := g.ToUndirected()
+	if u.Directed {
+		t.Fatalf("ToUndirected: expected an undirected graph")
+	}
+	if w, _ := u.edgeWeight(1, 2); w != 2 {
+		t.Errorf("ToUndirected: expected the smaller weight 2 for edge 1-2, got %d", w)
+	}
+	if !u.edgeExists(3, 2) || len(edgeList(&u)) != 2 {
+		t.Errorf("ToUndirected: unexpected edges %v", u.AdjacencyList)
+	}
+
+	d := u.ToDirected()
+	if !d.Directed || !d.edgeExists(1, 2) || !d.edgeExists(2, 1) || !d.edgeExists(3, 2) || len(edgeList(&d)) != 4 {
+		t.Errorf("ToDirected: unexpected edges %v", d.AdjacencyList)
+	}
+
+	// Converting to the same form only clones the graph.
+	if same := g.ToDirected(); !reflect.DeepEqual(same, g) {
+		t.Errorf("ToDirected on a directed graph = %v, want %v", same, g)
+	}
+}
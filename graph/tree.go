@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Rooted view of an undirected tree stored in a Graph, eg. a minimum spanning tree built from
+// the edges returned by KruskalMST with NewGraphFromEdges.
+// Parents and depths are computed once with a BFS from the root. For LCA queries with binary lifting,
+// up[k][v] is the 2^k-th ancestor of v (the root is its own ancestor). Arrays are indexed by node,
+// index 0 is unused.
+type RootedTree struct {
+	Root     int
+	Parent   []int // Parent of each node, 0 for the root.
+	Depth    []int // Number of edges from the root.
+	Distance []int // Total weight of the path from the root.
+	Children [][]int
+	up       [][]int
+	order    []int // Nodes in BFS order, parents always before children.
+}
+
+// Build a rooted view of the graph, which must be an undirected tree (connected, with n-1 edges).
+// Return an error if it's not a tree.
+func (g *Graph) RootedTree(root int) (*RootedTree, error) {
+	if g.Directed {
+		panic("RootedTree: cannot be applied to directed graphs.")
+	}
+	if root < 1 || root > g.Nodes {
+		panic(fmt.Sprintf("RootedTree: root should be in range [1, %v], got %v", g.Nodes, root))
+	}
+
+	edges := 0
+	for _, edgeList := range g.AdjacencyList {
+		edges += len(edgeList)
+	}
+	if edges/2 != g.Nodes-1 {
+		return nil, fmt.Errorf("RootedTree: a tree with %v nodes should have %v edges, got %v", g.Nodes, g.Nodes-1, edges/2)
+	}
+
+	t := &RootedTree{
+		Root:     root,
+		Parent:   make([]int, g.Nodes+1),
+		Depth:    make([]int, g.Nodes+1),
+		Distance: make([]int, g.Nodes+1),
+		Children: make([][]int, g.Nodes+1),
+	}
+
+	seen := NewSet()
+	queue := NewQueue()
+	seen.Add(root)
+	queue.Enqueue(root)
+	for queue.Length() > 0 {
+		v := queue.Dequeue()
+		t.order = append(t.order, v)
+		for _, edge := range g.AdjacencyList[v] {
+			if !seen.Contains(edge.To) {
+				seen.Add(edge.To)
+				t.Parent[edge.To] = v
+				t.Depth[edge.To] = t.Depth[v] + 1
+				t.Distance[edge.To] = t.Distance[v] + edge.Weight
+				t.Children[v] = append(t.Children[v], edge.To)
+				queue.Enqueue(edge.To)
+			}
+		}
+	}
+	// With n-1 edges, the graph is a tree exactly when it's connected.
+	if seen.Length() != g.Nodes {
+		return nil, fmt.Errorf("RootedTree: graph is not connected, only %v of %v nodes reachable from %v", seen.Length(), g.Nodes, root)
+	}
+
+	// Binary lifting table: the 2^k-th ancestor is the 2^(k-1)-th ancestor of the 2^(k-1)-th ancestor.
+	levels := max(1, bits.Len(uint(g.Nodes)))
+	t.up = make([][]int, levels)
+	t.up[0] = make([]int, g.Nodes+1)
+	for node := 1; node <= g.Nodes; node++ {
+		t.up[0][node] = t.Parent[node]
+	}
+	t.up[0][root] = root
+	for k := 1; k < levels; k++ {
+		t.up[k] = make([]int, g.Nodes+1)
+		for node := 1; node <= g.Nodes; node++ {
+			t.up[k][node] = t.up[k-1][t.up[k-1][node]]
+		}
+	}
+	return t, nil
+}
+
+// Get the number of nodes in the tree.
+func (t *RootedTree) Nodes() int {
+	return len(t.Parent) - 1
+}
+
+func (t *RootedTree) checkNode(name string, node int) {
+	if node < 1 || node > t.Nodes() {
+		panic(fmt.Sprintf("%v: node should be in range [1, %v], got %v", name, t.Nodes(), node))
+	}
+}
+
+// Get the ancestor of `node` that is `k` levels above it, or 0 if the tree isn't that deep.
+// The jump is split into powers of two, one for each bit set in k.
+func (t *RootedTree) Ancestor(node int, k int) int {
+	t.checkNode("Ancestor", node)
+	if k > t.Depth[node] {
+		return 0
+	}
+	for i := 0; k > 0; i++ {
+		if k&1 == 1 {
+			node = t.up[i][node]
+		}
+		k >>= 1
+	}
+	return node
+}
+
+// Get the lowest common ancestor of nodes `a` and `b` in O(log n). First lift the deeper node to the
+// depth of the other one. Then lift both by the largest jumps that keep them apart, after which
+// their parent is the LCA.
+func (t *RootedTree) LCA(a int, b int) int {
+	t.checkNode("LCA", a)
+	t.checkNode("LCA", b)
+	if t.Depth[a] < t.Depth[b] {
+		a, b = b, a
+	}
+	a = t.Ancestor(a, t.Depth[a]-t.Depth[b])
+	if a == b {
+		return a
+	}
+	for k := len(t.up) - 1; k >= 0; k-- {
+		if t.up[k][a] != t.up[k][b] {
+			a, b = t.up[k][a], t.up[k][b]
+		}
+	}
+	return t.Parent[a]
+}
+
+// Get the number of edges on the path between nodes `a` and `b`.
+func (t *RootedTree) EdgeDistance(a int, b int) int {
+	lca := t.LCA(a, b)
+	return t.Depth[a] + t.Depth[b] - 2*t.Depth[lca]
+}
+
+// Get the total weight of the path between nodes `a` and `b`.
+func (t *RootedTree) WeightedDistance(a int, b int) int {
+	lca := t.LCA(a, b)
+	return t.Distance[a] + t.Distance[b] - 2*t.Distance[lca]
+}
+
+// Get the size of the subtree of every node, including the node itself. Computed in reverse BFS order,
+// so the children are done before their parent.
+func (t *RootedTree) SubtreeSizes() []int {
+	sizes := make([]int, len(t.Parent))
+	for i := len(t.order) - 1; i >= 0; i-- {
+		node := t.order[i]
+		sizes[node]++
+		if node != t.Root {
+			sizes[t.Parent[node]] += sizes[node]
+		}
+	}
+	return sizes
+}
+
+// Find the diameter of the tree: the longest path between two nodes, counting edges (weights are ignored),
+// see WeightedDiameter. Return the path and its length.
+func (t *RootedTree) Diameter() ([]int, int) {
+	return t.diameter(t.EdgeDistance)
+}
+
+// Find the diameter of the tree by the total weight of the path, eg. the most expensive connection
+// in a minimum spanning tree. Weights should be non-negative. Return the path and its weight.
+func (t *RootedTree) WeightedDiameter() ([]int, int) {
+	return t.diameter(t.WeightedDistance)
+}
+
+// Find the longest path by the given distance with a double sweep: the furthest node from any node
+// is an end of some diameter, and the furthest node from that one is the other end.
+func (t *RootedTree) diameter(distance func(a int, b int) int) ([]int, int) {
+	first := t.furthest(t.Root, distance)
+	second := t.furthest(first, distance)
+	return t.Path(first, second), distance(first, second)
+}
+
+// Get the furthest node from `node` by the given distance, the smallest one in case of ties.
+func (t *RootedTree) furthest(node int, distance func(a int, b int) int) int {
+	best := node
+	for other := 1; other <= t.Nodes(); other++ {
+		if distance(node, other) > distance(node, best) {
+			best = other
+		}
+	}
+	return best
+}
+
+// Get the nodes on the path from `a` to `b`.
+func (t *RootedTree) Path(a int, b int) []int {
+	lca := t.LCA(a, b)
+	path := []int{}
+	for node := a; node != lca; node = t.Parent[node] {
+		path = append(path, node)
+	}
+	path = append(path, lca)
+	tail := []int{}
+	for node := b; node != lca; node = t.Parent[node] {
+		tail = append(tail, node)
+	}
+	for i := len(tail) - 1; i >= 0; i-- {
+		path = append(path, tail[i])
+	}
+	return path
+}
+
+// Find the center of the tree: the node (or two adjacent nodes) minimizing the maximum number of edges
+// to any other node, see WeightedCenter.
+func (t *RootedTree) Center() []int {
+	return t.center(t.EdgeDistance)
+}
+
+// Find the center of the tree by weight: the node (or two adjacent nodes) minimizing the maximum total
+// weight of the path to any other node. Weights should be non-negative.
+func (t *RootedTree) WeightedCenter() []int {
+	return t.center(t.WeightedDistance)
+}
+
+// Get the nodes minimizing the maximum distance to any other node, in increasing order. They're on
+// any diameter, and the furthest node from a node of the diameter is one of its ends.
+func (t *RootedTree) center(distance func(a int, b int) int) []int {
+	path, length := t.diameter(distance)
+	best, center := math.MaxInt, []int{}
+	for _, node := range path {
+		eccentricity := max(distance(path[0], node), length-distance(path[0], node))
+		if eccentricity < best {
+			best, center = eccentricity, []int{node}
+		} else if eccentricity == best {
+			center = append(center, node)
+		}
+	}
+	slices.Sort(center)
+	return center
+}
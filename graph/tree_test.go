@@ -0,0 +1,206 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// Get a random tree, each node i > 1 is connected to a random smaller node.
+func randomTree(r *rand.Rand, nodes int, maxWeight int) Graph {
+	g := NewEmptyGraph(false)
+	g.AddNodes(nodes)
+	for node := 2; node <= nodes; node++ {
+		g.ConnectNodes(node, r.Intn(node-1)+1, r.Intn(maxWeight)+1)
+	}
+	return g
+}
+
+func TestRootedTree(t *testing.T) {
+	//        1
+	//      /   \
+	//     2     3
+	//    / \     \
+	//   4   5     6
+	//             |
+	//             7
+	g := NewEmptyGraph(false)
+	g.AddNodes(7)
+	g.ConnectNodes(1, 2, 1)
+	g.ConnectNodes(1, 3, 2)
+	g.ConnectNodes(2, 4, 3)
+	g.ConnectNodes(2, 5, 4)
+	g.ConnectNodes(3, 6, 5)
+	g.ConnectNodes(6, 7, 6)
+
+	tree, err := g.RootedTree(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(tree.Parent, []int{0, 0, 1, 1, 2, 2, 3, 6}) {
+		t.Errorf("Parent = %v", tree.Parent)
+	}
+	if !reflect.DeepEqual(tree.Depth, []int{0, 0, 1, 1, 2, 2, 2, 3}) {
+		t.Errorf("Depth = %v", tree.Depth)
+	}
+	if !reflect.DeepEqual(tree.SubtreeSizes(), []int{0, 7, 3, 3, 1, 1, 2, 1}) {
+		t.Errorf("SubtreeSizes = %v", tree.SubtreeSizes())
+	}
+
+	lcaTests := []struct{ a, b, lca int }{{4, 5, 2}, {4, 7, 1}, {6, 7, 6}, {7, 7, 7}, {3, 1, 1}}
+	for _, tt := range lcaTests {
+		if lca := tree.LCA(tt.a, tt.b); lca != tt.lca {
+			t.Errorf("LCA(%d, %d) = %d, want %d", tt.a, tt.b, lca, tt.lca)
+		}
+	}
+	if d := tree.EdgeDistance(4, 7); d != 5 {
+		t.Errorf("EdgeDistance(4, 7) = %d, want 5", d)
+	}
+	if d := tree.WeightedDistance(4, 7); d != 3+1+2+5+6 {
+		t.Errorf("WeightedDistance(4, 7) = %d, want 17", d)
+	}
+	if a := tree.Ancestor(7, 2); a != 3 {
+		t.Errorf("Ancestor(7, 2) = %d, want 3", a)
+	}
+	if a := tree.Ancestor(7, 4); a != 0 {
+		t.Errorf("Ancestor(7, 4) = %d, want 0", a)
+	}
+
+	path, length := tree.Diameter()
+	// The diameter is [4 2 1 3 6 7] or [5 2 1 3 6 7], in either direction.
+	if length != 5 || len(path) != 6 || !slicesEqual(path[1:5], []int{2, 1, 3, 6}) && !slicesEqual(path[1:5], []int{6, 3, 1, 2}) {
+		t.Errorf("Diameter = %v, %d; want a path of length 5 through 2, 1, 3, 6", path, length)
+	}
+	if center := tree.Center(); !slicesEqual(center, []int{1, 3}) {
+		t.Errorf("Center = %v, want [1 3]", center)
+	}
+	// Node 5 is further than 4 by weight.
+	if path, weight := tree.WeightedDiameter(); weight != 18 || !slicesEqual(path, []int{5, 2, 1, 3, 6, 7}) && !slicesEqual(path, []int{7, 6, 3, 1, 2, 5}) {
+		t.Errorf("WeightedDiameter = %v, %d; want [5 2 1 3 6 7] with weight 18", path, weight)
+	}
+	if center := tree.WeightedCenter(); !slicesEqual(center, []int{3}) {
+		t.Errorf("WeightedCenter = %v, want [3]", center)
+	}
+
+	// The longest path by edges, 4-3-2-1-5, is not the heaviest one, 5-1-6.
+	edges := []Edge{{From: 1, To: 2, Weight: 1}, {From: 2, To: 3, Weight: 1}, {From: 3, To: 4, Weight: 1}, {From: 1, To: 5, Weight: 10}, {From: 1, To: 6, Weight: 10}}
+	g = NewGraphFromEdges(6, edges, false)
+	tree, _ = g.RootedTree(1)
+	if _, length := tree.Diameter(); length != 4 {
+		t.Errorf("Diameter length = %d, want 4", length)
+	}
+	if path, weight := tree.WeightedDiameter(); weight != 20 || len(path) != 3 || path[1] != 1 {
+		t.Errorf("WeightedDiameter = %v, %d; want a path through 1 with weight 20", path, weight)
+	}
+	if center := tree.WeightedCenter(); !slicesEqual(center, []int{1}) {
+		t.Errorf("WeightedCenter = %v, want [1]", center)
+	}
+}
+
+func TestRootedTreeErrors(t *testing.T) {
+	cycle := NewEmptyGraph(false)
+	cycle.AddNodes(3)
+	cycle.ConnectNodes(1, 2, 1)
+	cycle.ConnectNodes(2, 3, 1)
+	cycle.ConnectNodes(3, 1, 1)
+	if _, err := cycle.RootedTree(1); err == nil {
+		t.Errorf("Expected an error for a graph with a cycle")
+	}
+
+	// Right number of edges, but a cycle and an isolated node.
+	disconnected := NewEmptyGraph(false)
+	disconnected.AddNodes(4)
+	disconnected.ConnectNodes(1, 2, 1)
+	disconnected.ConnectNodes(2, 3, 1)
+	disconnected.ConnectNodes(3, 1, 1)
+	if _, err := disconnected.RootedTree(1); err == nil {
+		t.Errorf("Expected an error for a disconnected graph")
+	}
+}
+
+// Compare with brute force on random trees: walking up the parents for LCA, Dijkstra for
+// distances, and eccentricities for the diameter and the center.
+func TestRootedTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(20))
+	for i := 0; i < 50; i++ {
+		g := randomTree(r, r.Intn(40)+1, 10)
+		tree, err := g.RootedTree(r.Intn(g.Nodes) + 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		eccentricity := make([]int, g.Nodes+1)
+		weightedEccentricity := make([]int, g.Nodes+1)
+		for a := 1; a <= g.Nodes; a++ {
+			dist, _ := g.Dijkstra(a)
+			for b := 1; b <= g.Nodes; b++ {
+				ancestors := map[int]bool{}
+				for v := a; v != 0; v = tree.Parent[v] {
+					ancestors[v] = true
+				}
+				expected := b
+				for !ancestors[expected] {
+					expected = tree.Parent[expected]
+				}
+				if lca := tree.LCA(a, b); lca != expected {
+					t.Fatalf("LCA(%d, %d) = %d, want %d", a, b, lca, expected)
+				}
+				if d := tree.WeightedDistance(a, b); d != dist[b] {
+					t.Fatalf("WeightedDistance(%d, %d) = %d, want %d", a, b, d, dist[b])
+				}
+				eccentricity[a] = max(eccentricity[a], len(tree.Path(a, b))-1)
+				weightedEccentricity[a] = max(weightedEccentricity[a], dist[b])
+			}
+		}
+
+		diameter, radius := 0, g.Nodes
+		weightedDiameter, weightedRadius := 0, math.MaxInt
+		for node := 1; node <= g.Nodes; node++ {
+			diameter = max(diameter, eccentricity[node])
+			radius = min(radius, eccentricity[node])
+			weightedDiameter = max(weightedDiameter, weightedEccentricity[node])
+			weightedRadius = min(weightedRadius, weightedEccentricity[node])
+		}
+		if _, length := tree.Diameter(); length != diameter {
+			t.Fatalf("Diameter: got %d, want %d", length, diameter)
+		}
+		for _, node := range tree.Center() {
+			if eccentricity[node] != radius {
+				t.Fatalf("Center: node %d has eccentricity %d, want %d", node, eccentricity[node], radius)
+			}
+		}
+
+		if path, weight := tree.WeightedDiameter(); weight != weightedDiameter || tree.WeightedDistance(path[0], path[len(path)-1]) != weight {
+			t.Fatalf("WeightedDiameter: got path %v with weight %d, want weight %d", path, weight, weightedDiameter)
+		}
+		center := tree.WeightedCenter()
+		for _, node := range center {
+			if weightedEccentricity[node] != weightedRadius {
+				t.Fatalf("WeightedCenter: node %d has eccentricity %d, want %d", node, weightedEccentricity[node], weightedRadius)
+			}
+		}
+		for node := 1; node <= g.Nodes; node++ {
+			if weightedEccentricity[node] == weightedRadius && !slices.Contains(center, node) {
+				t.Fatalf("WeightedCenter = %v, missing node %d", center, node)
+			}
+		}
+
+		sizes := tree.SubtreeSizes()
+		if sizes[tree.Root] != g.Nodes {
+			t.Fatalf("SubtreeSizes: root subtree has %d nodes, want %d", sizes[tree.Root], g.Nodes)
+		}
+	}
+}
+
+// The result of KruskalMST can be turned into a tree.
+func TestRootedTreeFromMST(t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	g := randomGraph(r, 10, false, 1, 20)
+
+	mst := NewGraphFromEdges(g.Nodes, g.KruskalMST(), false)
+	if _, err := mst.RootedTree(1); err != nil {
+		t.Errorf("Expected the MST to be a tree, got %v", err)
+	}
+}
@@ -197,10 +197,13 @@ func (u *UnionFind) Union(k int, l int) {
 // Priority queue (based on mean heap) implementation.
 // Used in: dijkstra.go in Dijkstra's algorithm,
 //          prim.go in Prim's algorithm
+//          parallel.go in delta-stepping
 // ============================
 
 // Min heap, which can be used as a priority queue. All the heap operations work on `priorities`,
 // but we can associate `values` with them. An auxiliary hash map (indexMap) is used for quick access.
+// Values with equal priorities are popped in increasing order, so the order doesn't depend on
+// the order of insertions.
 type Heap struct {
 	Priorities []int
 	Values     []int
@@ -222,6 +225,14 @@ func parent(i int) int {
 	return (i - 1) / 2
 }
 
+// Check if the element at index i comes before the one at index j, ties are broken by value.
+func (h *Heap) less(i, j int) bool {
+	if h.Priorities[i] != h.Priorities[j] {
+		return h.Priorities[i] < h.Priorities[j]
+	}
+	return h.Values[i] < h.Values[j]
+}
+
 // Ensure that the subtree rooted at 'index' satisfies the min-heap property.
 func (h *Heap) heapify(index int) {
 	smallest := index
@@ -229,12 +240,12 @@ func (h *Heap) heapify(index int) {
 	rightIndex := right(index)
 
 	// Check if left child exists and is smaller than the current node.
-	if leftIndex < len(h.Priorities) && h.Priorities[leftIndex] < h.Priorities[smallest] {
+	if leftIndex < len(h.Priorities) && h.less(leftIndex, smallest) {
 		smallest = leftIndex
 	}
 
 	// Check if right child exists and is smaller than the smallest found so far.
-	if rightIndex < len(h.Priorities) && h.Priorities[rightIndex] < h.Priorities[smallest] {
+	if rightIndex < len(h.Priorities) && h.less(rightIndex, smallest) {
 		smallest = rightIndex
 	}
 
@@ -298,7 +309,7 @@ func (h *Heap) Push(priority int, value int) {
 	index := len(h.Priorities) - 1
 	h.IndexMap[value] = index
 
-	for index > 0 && h.Priorities[index] < h.Priorities[parent(index)] {
+	for index > 0 && h.less(index, parent(index)) {
 		h.swap(index, parent(index))
 		index = parent(index)
 	}
@@ -344,7 +355,7 @@ func (h *Heap) DecreasePrio(value int, newPriority int) error {
 	h.Priorities[index] = newPriority
 
 	// Bubble up the element to restore the heap property.
-	for index > 0 && h.Priorities[index] < h.Priorities[parent(index)] {
+	for index > 0 && h.less(index, parent(index)) {
 		h.swap(index, parent(index))
 		index = parent(index)
 	}
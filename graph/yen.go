@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// A path in a graph, as a sequence of nodes, with its total weight.
+type Path struct {
+	Nodes  []int
+	Weight int
+}
+
+// Reconstruct the path from `source` to `target` using the predecessor map returned by Dijkstra.
+// Return nil if the target is unreachable.
+func pathTo(prev map[int]int, source int, target int) []int {
+	if target != source && prev[target] == -1 {
+		return nil
+	}
+	path := []int{}
+	for node := target; node != source; node = prev[node] {
+		path = append(path, node)
+	}
+	path = append(path, source)
+	slices.Reverse(path) // The path was built backwards.
+	return path
+}
+
+// Get the total weight of a path. Panic if some of its edges don't exist.
+func (g *Graph) pathWeight(path []int) int {
+	weight := 0
+	for i := 0; i+1 < len(path); i++ {
+		w, exists := g.edgeWeight(path[i], path[i+1])
+		if !exists {
+			panic(fmt.Sprintf("pathWeight: there is no edge between %v and %v", path[i], path[i+1]))
+		}
+		weight += w
+	}
+	return weight
+}
+
+// Yen's algorithm for the K shortest loopless paths from `source` to `target`.
+// The first path is the one found by Dijkstra. Each next path deviates from one of the paths found
+// so far: for every node of the previous path (the spur node), take the part of the path before it
+// (the root path), forbid the edges that the already found paths with the same root take out of
+// the spur node, forbid the nodes of the root path, and run Dijkstra from the spur node on what's left.
+// Root path + spur path is a candidate, and the cheapest candidate becomes the next path.
+//
+// Return up to k paths in order of increasing weight, ties are broken deterministically by lessPath.
+// Weights should be positive, as Dijkstra requires.
+func (g *Graph) KShortestPaths(source int, target int, k int) []Path {
+	if source < 1 || source > g.Nodes || target < 1 || target > g.Nodes {
+		panic(fmt.Sprintf("KShortestPaths: source and target should be in range [1, %v], got %v and %v", g.Nodes, source, target))
+	}
+	if k < 1 {
+		panic(fmt.Sprintf("KShortestPaths: k should be positive, got %v", k))
+	}
+
+	dist, prev := g.Dijkstra(source)
+	if dist[target] == math.MaxInt {
+		return []Path{}
+	}
+	found := []Path{{Nodes: pathTo(prev, source, target), Weight: dist[target]}}
+	candidates := []Path{}
+	seen := map[string]bool{fmt.Sprint(found[0].Nodes): true} // To avoid duplicate candidates.
+
+	for len(found) < k {
+		last := found[len(found)-1].Nodes
+		for i := 0; i < len(last)-1; i++ {
+			spurNode := last[i]
+			rootPath := last[:i+1]
+
+			// Forbid edges that would lead back to an already found path with the same root.
+			forbiddenEdges := make(map[[2]int]bool)
+			for _, p := range found {
+				if len(p.Nodes) > i+1 && slices.Equal(p.Nodes[:i+1], rootPath) {
+					forbiddenEdges[[2]int{p.Nodes[i], p.Nodes[i+1]}] = true
+				}
+			}
+			// Forbid nodes of the root path, except the spur node, so that the path stays loopless.
+			forbiddenNodes := make(map[int]bool)
+			for _, node := range rootPath[:i] {
+				forbiddenNodes[node] = true
+			}
+
+			reduced := g.withoutEdges(forbiddenNodes, forbiddenEdges)
+			spurDist, spurPrev := reduced.Dijkstra(spurNode)
+			if spurDist[target] == math.MaxInt {
+				continue
+			}
+
+			nodes := append(append([]int{}, rootPath[:i]...), pathTo(spurPrev, spurNode, target)...)
+			key := fmt.Sprint(nodes)
+			if !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, Path{Nodes: nodes, Weight: g.pathWeight(nodes)})
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			return lessPath(candidates[a], candidates[b])
+		})
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+	return found
+}
+
+// Compare paths by weight, then by length, then by nodes, so that the order is deterministic.
+func lessPath(a Path, b Path) bool {
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
+	}
+	if len(a.Nodes) != len(b.Nodes) {
+		return len(a.Nodes) < len(b.Nodes)
+	}
+	for i := range a.Nodes {
+		if a.Nodes[i] != b.Nodes[i] {
+			return a.Nodes[i] < b.Nodes[i]
+		}
+	}
+	return false
+}
+
+// Get a copy of the graph without the given nodes (all their edges are removed, the nodes themselves
+// stay, so the numbering doesn't change) and without the given directed edges.
+func (g *Graph) withoutEdges(nodes map[int]bool, edges map[[2]int]bool) Graph {
+	reduced := NewEmptyGraph(g.Directed)
+	if g.Nodes > 0 {
+		reduced.AddNodes(g.Nodes)
+	}
+	for from := 1; from <= g.Nodes; from++ {
+		if nodes[from] {
+			continue
+		}
+		for _, edge := range g.AdjacencyList[from] {
+			if !nodes[edge.To] && !edges[[2]int{from, edge.To}] {
+				reduced.AdjacencyList[from] = append(reduced.AdjacencyList[from], edge)
+			}
+		}
+	}
+	return reduced
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Enumerate all simple paths from source to target, sorted the same way as KShortestPaths.
+func allSimplePaths(g *Graph, source int, target int) []Path {
+	paths := []Path{}
+	path := []int{source}
+	onPath := map[int]bool{source: true}
+	var walk func(v int, weight int)
+	walk = func(v int, weight int) {
+		if v == target {
+			paths = append(paths, Path{Nodes: append([]int{}, path...), Weight: weight})
+			return
+		}
+		for _, edge := range g.AdjacencyList[v] {
+			if !onPath[edge.To] {
+				onPath[edge.To] = true
+				path = append(path, edge.To)
+				walk(edge.To, weight+edge.Weight)
+				path = path[:len(path)-1]
+				onPath[edge.To] = false
+			}
+		}
+	}
+	walk(source, 0)
+	sort.Slice(paths, func(a, b int) bool { return lessPath(paths[a], paths[b]) })
+	return paths
+}
+
+func TestKShortestPaths(t *testing.T) {
+	// Example from the Wikipedia article on Yen's algorithm, C=1, D=2, E=3, F=4, G=5, H=6.
+	g := NewEmptyGraph(true)
+	g.AddNodes(6)
+	g.ConnectNodes(1, 2, 3)
+	g.ConnectNodes(1, 3, 2)
+	g.ConnectNodes(2, 4, 4)
+	g.ConnectNodes(3, 2, 1)
+	g.ConnectNodes(3, 4, 2)
+	g.ConnectNodes(3, 5, 3)
+	g.ConnectNodes(4, 5, 2)
+	g.ConnectNodes(4, 6, 1)
+	g.ConnectNodes(5, 6, 2)
+
+	expected := []Path{
+		{Nodes: []int{1, 3, 4, 6}, Weight: 5},
+		{Nodes: []int{1, 3, 5, 6}, Weight: 7},
+		{Nodes: []int{1, 2, 4, 6}, Weight: 8},
+	}
+	if got := g.KShortestPaths(1, 6, 3); !reflect.DeepEqual(got, expected) {
+		t.Errorf("KShortestPaths = %v, want %v", got, expected)
+	}
+
+	// Unreachable target and source equal to target.
+	if got := g.KShortestPaths(6, 1, 3); len(got) != 0 {
+		t.Errorf("KShortestPaths to an unreachable node = %v, want no paths", got)
+	}
+	if got := g.KShortestPaths(2, 2, 3); !reflect.DeepEqual(got, []Path{{Nodes: []int{2}, Weight: 0}}) {
+		t.Errorf("KShortestPaths from a node to itself = %v", got)
+	}
+}
+
+// Compare with enumerating all simple paths on random graphs.
+func TestKShortestPathsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		g := randomGraph(r, r.Intn(6)+2, i%2 == 0, r.Float64()*0.7, 10)
+		source, target := r.Intn(g.Nodes)+1, r.Intn(g.Nodes)+1
+		k := r.Intn(8) + 1
+
+		all := allSimplePaths(&g, source, target)
+		got := g.KShortestPaths(source, target, k)
+		if len(got) != min(k, len(all)) {
+			t.Fatalf("KShortestPaths(%d, %d, %d): got %d paths, want %d", source, target, k, len(got), min(k, len(all)))
+		}
+		// Weights must match exactly, paths can differ only among ties.
+		for j := range got {
+			if got[j].Weight != all[j].Weight || got[j].Weight != g.pathWeight(got[j].Nodes) {
+				t.Fatalf("KShortestPaths(%d, %d, %d) = %v, want %v", source, target, k, got, all[:len(got)])
+			}
+		}
+	}
+}
+
+func TestKShortestPathsPanics(t *testing.T) {
+	g := NewEmptyGraph(false)
+	g.AddNodes(2)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected KShortestPaths to panic for k < 1")
+		}
+	}()
+	g.KShortestPaths(1, 2, 0)
+}